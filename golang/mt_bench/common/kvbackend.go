@@ -0,0 +1,46 @@
+package common
+
+import (
+	"fmt"
+
+	"github.com/cosmos/iavl/db"
+)
+
+// KVBackend は IAVL ベンチマークが書き込む先のストレージエンジンを切り替え可能にする
+// ための抽象です。db.DB を開いて返すだけの薄いラッパーであり、ツリー構造のコストと
+// 下層 KVS のコストを切り分けて比較できるようにするために導入されました。
+type KVBackend interface {
+	// Open は path にデータベースを作成 (または既存のものをロード) する。
+	Open(path string) error
+	Close() error
+	DB() db.DB
+}
+
+// kvBackends は登録済みの KVBackend コンストラクタ名。各アダプタは init() で
+// RegisterKVBackend を呼び出して自身を登録する。
+var kvBackends = map[string]func() KVBackend{}
+
+// RegisterKVBackend は name で NewKVBackend から参照できる KVBackend のコンストラクタを
+// 登録する。
+func RegisterKVBackend(name string, factory func() KVBackend) {
+	kvBackends[name] = factory
+}
+
+// NewKVBackend は name に対応する KVBackend を生成する。未登録の name が指定された場合は
+// panic する。
+func NewKVBackend(name string) KVBackend {
+	factory, ok := kvBackends[name]
+	if !ok {
+		panic(fmt.Errorf("unknown kv backend: %s", name))
+	}
+	return factory()
+}
+
+// KVBackendNames は登録されている KVBackend の名前を返す (--backend フラグのヘルプ表示用)。
+func KVBackendNames() []string {
+	names := make([]string, 0, len(kvBackends))
+	for name := range kvBackends {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,201 @@
+package common
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// OpType はミックスドワークロード中の単一操作の種別。
+type OpType int
+
+const (
+	OpRead OpType = iota
+	OpInsert
+	OpUpdate
+	OpDelete
+)
+
+// String は CSV のファイル名や見出しに使う短い識別子を返す。
+func (t OpType) String() string {
+	switch t {
+	case OpRead:
+		return "read"
+	case OpInsert:
+		return "insert"
+	case OpUpdate:
+		return "update"
+	case OpDelete:
+		return "delete"
+	default:
+		return "unknown"
+	}
+}
+
+// KeyDist はミックスドワークロードが鍵を選ぶ際の分布。
+type KeyDist int
+
+const (
+	Uniform KeyDist = iota
+	Zipfian
+	Latest
+)
+
+// Op はミックスドワークロード内の 1 回の操作。
+type Op struct {
+	Type OpType
+	Key  uint64
+}
+
+// Workload は YCSB スタイルのワークロード記述子。ReadPct/InsertPct/UpdatePct/DeletePct の
+// 合計は 100 でなければならない。
+type Workload struct {
+	ReadPct, InsertPct, UpdatePct, DeletePct int
+	KeyDist                                  KeyDist
+}
+
+// 標準的な YCSB ワークロードのプリセット。詳細は YCSB の Core Workloads を参照。
+var (
+	// YCSBWorkloadA は Update heavy: 50% read, 50% update。
+	YCSBWorkloadA = Workload{ReadPct: 50, UpdatePct: 50, KeyDist: Zipfian}
+	// YCSBWorkloadB は Read mostly: 95% read, 5% update。
+	YCSBWorkloadB = Workload{ReadPct: 95, UpdatePct: 5, KeyDist: Zipfian}
+	// YCSBWorkloadC は Read only: 100% read。
+	YCSBWorkloadC = Workload{ReadPct: 100, KeyDist: Zipfian}
+	// YCSBWorkloadD は Read latest: 95% read, 5% insert。直近に挿入された鍵が読まれやすい。
+	YCSBWorkloadD = Workload{ReadPct: 95, InsertPct: 5, KeyDist: Latest}
+	// YCSBWorkloadF は Read-modify-write: 50% read, 50% update (同じ鍵に対して連続して発行)。
+	YCSBWorkloadF = Workload{ReadPct: 50, UpdatePct: 50, KeyDist: Zipfian}
+)
+
+// YCSBWorkloads は --workload フラグで選択可能な名前とプリセットの対応表。
+var YCSBWorkloads = map[string]Workload{
+	"a": YCSBWorkloadA,
+	"b": YCSBWorkloadB,
+	"c": YCSBWorkloadC,
+	"d": YCSBWorkloadD,
+	"f": YCSBWorkloadF,
+}
+
+// GenerateOps は workload の比率と鍵分布に従って n 件の操作列を生成する。count は呼び出し
+// 時点でデータベースに存在する鍵の個数 (挿入カーソル) を指し、Insert 操作のたびに進められる。
+func GenerateOps(workload Workload, n int, count *uint64) []Op {
+	ops := make([]Op, n)
+	for i := 0; i < n; i++ {
+		roll := rand.Intn(100)
+		var opType OpType
+		switch {
+		case roll < workload.ReadPct:
+			opType = OpRead
+		case roll < workload.ReadPct+workload.InsertPct:
+			opType = OpInsert
+		case roll < workload.ReadPct+workload.InsertPct+workload.UpdatePct:
+			opType = OpUpdate
+		default:
+			opType = OpDelete
+		}
+
+		var key uint64
+		if opType == OpInsert || *count == 0 {
+			*count++
+			key = *count
+		} else {
+			key = pickKey(workload.KeyDist, *count)
+		}
+		ops[i] = Op{Type: opType, Key: key}
+	}
+	return ops
+}
+
+// keyRand はワークロード生成時の鍵選択にのみ用いる乱数源。count ごとに毎回シードを切り
+// 直すと、count が変わらない間 (挿入が起きていない連続した read/update の間) ずっと同じ鍵
+// しか選ばれなくなってしまうため、呼び出しをまたいで状態を持ち越す共有の乱数源を使う。
+var keyRand = rand.New(rand.NewSource(1))
+
+// pickKey は count 件の既存鍵 (1..count) の中から dist に従って 1 件を選ぶ。
+func pickKey(dist KeyDist, count uint64) uint64 {
+	switch dist {
+	case Zipfian:
+		// 典型的な YCSB ワークロードのホットセットを模し、番号の小さい鍵ほど選ばれやすい。
+		z := rand.NewZipf(keyRand, 1.5, 1, count-1)
+		return z.Uint64() + 1
+	case Latest:
+		// 直近に挿入された鍵ほど選ばれやすいよう、Zipf 分布を鍵空間の末尾側に畳み込む。
+		z := rand.NewZipf(keyRand, 1.5, 1, count-1)
+		return count - z.Uint64()
+	default: // Uniform
+		return uint64(keyRand.Int63n(int64(count))) + 1
+	}
+}
+
+// mixedStatsKey は Stats が (opType, dataSize) の組ごとに別系列としてレイテンシを集計
+// できるよう、両者を 1 つの uint64 キーに符号化する。OpType は高々 4 種類なので下位 3 ビット
+// に詰め、残りに dataSize を入れる。
+func mixedStatsKey(opType OpType, dataSize uint64) uint64 {
+	return dataSize<<3 | uint64(opType)
+}
+
+// isMixedConvergent は、観測されたすべての操作種別について CV が収束しているかを判定する。
+func isMixedConvergent(stats *Stats, dataSize uint64) bool {
+	for _, opType := range []OpType{OpRead, OpInsert, OpUpdate, OpDelete} {
+		key := mixedStatsKey(opType, dataSize)
+		if _, _, n := stats.Calculate(key); n == 0 {
+			continue
+		}
+		if !stats.IsCVSufficient(key, CVThreshold) {
+			return false
+		}
+	}
+	return true
+}
+
+// BenchmarkMixed は YCSB ライクな読み書き混在ワークロードをベンチマークする。measureOp は
+// バックエンド固有の ops 列の実行を担うコールバックで、ops と同じ順序・長さの実測レイテンシ
+// を返す。操作種別ごとの CV を個別に追跡することで、特定の操作だけがテール遅延を持つケース
+// を append/query の単独測定では見逃してしまう問題に対応する。
+func BenchmarkMixed(
+	config *Config,
+	id string,
+	workload Workload,
+	workloadSize int,
+	measureAppend func(string, uint64) (time.Duration, uint64),
+	measureOp func(path string, ops []Op) []time.Duration,
+) {
+	fmt.Printf("\n=== Mixed Workload Benchmark (%s) ===\n", id)
+
+	fmt.Printf("Preparing database with %d entries: ", config.DataSize)
+	config.RemoveDatabase(id)
+	t0 := time.Now()
+	measureAppend(config.DatabasePath(id), config.DataSize)
+	tm := time.Since(t0)
+	fmt.Printf("done: %.3f [msec]\n", float64(tm.Nanoseconds())/1000.0/1000.0)
+
+	count := config.DataSize
+	stats := NewStats()
+	start := time.Now()
+	for i := 0; i < MaxTrials; i++ {
+		ops := GenerateOps(workload, workloadSize, &count)
+		durations := measureOp(config.DatabasePath(id), ops)
+		for j, duration := range durations {
+			key := mixedStatsKey(ops[j].Type, config.DataSize)
+			stats.Add(key, float64(duration.Nanoseconds())/1000.0/1000.0)
+		}
+		if i+1 >= MinTrials {
+			if isMixedConvergent(stats, config.DataSize) || time.Since(start) >= MaxDuration {
+				break
+			}
+		}
+		if (i+1)%100 == 0 {
+			fmt.Printf("  [%d/%d] trials completed\n", i+1, MaxTrials)
+		}
+	}
+	config.RemoveDatabase(id)
+
+	for _, opType := range []OpType{OpRead, OpInsert, OpUpdate, OpDelete} {
+		key := mixedStatsKey(opType, config.DataSize)
+		if _, _, n := stats.Calculate(key); n == 0 {
+			continue
+		}
+		stats.Save(config.ResultFile(fmt.Sprintf("%s-%s", id, opType)), "SIZE", "MILLISECONDS")
+	}
+}
@@ -3,6 +3,7 @@ package common
 import (
 	"encoding/binary"
 	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"io/fs"
 	"math"
@@ -29,10 +30,34 @@ const (
 
 // コマンドライン引数
 type Config struct {
-	DataSize  uint64
-	WorkDir   string
-	ResultDir string
-	SessionID string
+	DataSize        uint64
+	WorkDir         string
+	ResultDir       string
+	SessionID       string
+	Backend         string
+	LevelDB         LevelDBOptions
+	Workload        string
+	PromPushgateway string
+	CommitBatch     uint64
+}
+
+// LevelDBOptions は goleveldb のチューニングパラメータのうち性能を大きく左右するものを
+// ベンチマークから調整・記録できるようにしたもの。これらの値を残しておかないと、同じ
+// ベンチマークを別の環境で再現したり、環境間で結果を比較したりすることができない。
+type LevelDBOptions struct {
+	CacheMB         int  `json:"cache_mb"`
+	WriteBufferMB   int  `json:"write_buffer_mb"`
+	BloomFilterBits int  `json:"bloom_filter_bits"`
+	BlockSizeKB     int  `json:"block_size_kb"`
+	Compression     bool `json:"compression"`
+}
+
+// LevelDBTunable は goleveldb ライクなチューニングパラメータを受け取れる KVBackend が
+// 実装するオプションのインタフェース。goleveldb 以外のバックエンドはこれを実装しないため、
+// 型アサーションで安全にスキップできる。
+type LevelDBTunable interface {
+	KVBackend
+	Tune(opts LevelDBOptions)
 }
 
 // Append 性能のベンチマーク
@@ -75,6 +100,7 @@ func BenchmarkAppend(
 
 	timeComplexity.Save(config.ResultFile(append_id), "SIZE", "MILLISECONDS")
 	spaceComplexity.Save(config.ResultFile(volume_id), "SIZE", "BYTES")
+	config.pushProm(timeComplexity, "append")
 }
 
 // Query 性能のベンチマーク
@@ -121,6 +147,19 @@ func BenchmarkQuery(
 	config.RemoveDatabase(query_id)
 
 	timeComplexity.Save(config.ResultFile(query_id), "SIZE", "TIME")
+	config.pushProm(timeComplexity, "query")
+}
+
+// pushProm は config.PromPushgateway が設定されている場合に限り、stats のスナップショットを
+// Prometheus Pushgateway へ送信する。送信に失敗してもベンチマーク自体は継続させたいので、
+// エラーは標準エラー出力に残すだけに留める。
+func (c *Config) pushProm(stats *Stats, op string) {
+	if c.PromPushgateway == "" {
+		return
+	}
+	if err := stats.PushProm(c.PromPushgateway, "slate_bench", op, "slate_benchmark", c.SessionID); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to push metrics to pushgateway: %v\n", err)
+	}
 }
 
 // コマンドライン引数の解析
@@ -130,6 +169,7 @@ func ParseCommandLine(names []string, short, long string) *Config {
 		WorkDir:   "",
 		ResultDir: "",
 		SessionID: "",
+		Backend:   "goleveldb",
 	}
 
 	rootCmd := &cobra.Command{
@@ -152,6 +192,15 @@ func ParseCommandLine(names []string, short, long string) *Config {
 	workDirFlag := flags.StringP("dir", "d", os.TempDir(), "Database directory used for benchmarking")
 	resultDirFlag := flags.StringP("output", "o", DefaultResultDir, "Directory to save result CSV files")
 	sessionIdFlag := flags.StringP("session", "s", time.Now().Format("20060102150405"), "Session name for result file naming")
+	backendFlag := flags.String("backend", "goleveldb", fmt.Sprintf("KV backend to open the IAVL tree on (one of: %v)", KVBackendNames()))
+	ldbCacheFlag := flags.Int("ldb-cache", 8, "goleveldb block cache size in MiB")
+	ldbWriteBufferFlag := flags.Int("ldb-write-buffer", 4, "goleveldb write buffer size in MiB")
+	ldbBloomBitsFlag := flags.Int("ldb-bloom-bits", 0, "goleveldb bloom filter bits per key (0 disables the filter)")
+	ldbBlockSizeFlag := flags.Int("ldb-block-size", 4, "goleveldb block size in KiB")
+	ldbCompressionFlag := flags.Bool("ldb-compression", true, "enable goleveldb Snappy compression")
+	workloadFlag := flags.String("workload", "", "run a YCSB-style mixed workload instead of pure append/query (one of: a, b, c, d, f)")
+	promPushgatewayFlag := flags.String("prom-pushgateway", "", "Prometheus Pushgateway URL to push the final result snapshot to")
+	commitBatchFlag := flags.Uint64("commit-batch", 1, "number of Set calls between SaveVersion calls (1 commits a version per key, like a real Cosmos SDK block would not)")
 	cleanFlag := flags.BoolP("clean", "c", false, "Remove all cached files and exit")
 
 	if err := rootCmd.Execute(); err != nil {
@@ -162,6 +211,17 @@ func ParseCommandLine(names []string, short, long string) *Config {
 	config.WorkDir = CreateDirectory(*workDirFlag)
 	config.ResultDir = CreateDirectory(*resultDirFlag)
 	config.SessionID = *sessionIdFlag
+	config.Backend = *backendFlag
+	config.LevelDB = LevelDBOptions{
+		CacheMB:         *ldbCacheFlag,
+		WriteBufferMB:   *ldbWriteBufferFlag,
+		BloomFilterBits: *ldbBloomBitsFlag,
+		BlockSizeKB:     *ldbBlockSizeFlag,
+		Compression:     *ldbCompressionFlag,
+	}
+	config.Workload = *workloadFlag
+	config.PromPushgateway = *promPushgatewayFlag
+	config.CommitBatch = *commitBatchFlag
 
 	if *cleanFlag {
 		for _, name := range names {
@@ -181,6 +241,7 @@ func PrintSystemInfo(title, dbType string, config *Config) {
 	fmt.Printf("Working directory: %s\n", config.WorkDir)
 	fmt.Printf("Result directory: %s\n", config.ResultDir)
 	fmt.Printf("Session ID: %s\n", config.SessionID)
+	fmt.Printf("KV backend: %s\n", config.Backend)
 	fmt.Printf("Max data size: %d\n", config.DataSize)
 	fmt.Printf("Max trials: %d\n", MaxTrials)
 	fmt.Printf("Min trials: %d\n", MinTrials)
@@ -311,6 +372,28 @@ func (c *Config) ResultFile(id string) string {
 	return filepath.Join(c.ResultDir, fmt.Sprintf("%s-%s.csv", c.SessionID, id))
 }
 
+// ResultSidecarFile は id に対応する CSV と対になる、ベンチマーク条件を記録するための
+// JSON ファイルのパスを返す。
+func (c *Config) ResultSidecarFile(id string) string {
+	return filepath.Join(c.ResultDir, fmt.Sprintf("%s-%s.json", c.SessionID, id))
+}
+
+// SaveLevelDBOptions は選択された goleveldb のチューニングパラメータを id に対応する
+// サイドカー JSON として書き出す。CSV 本体にパラメータを混ぜず分離しておくことで、
+// 異なる環境・パラメータで取得した結果を後から区別・比較できるようにする。
+func (c *Config) SaveLevelDBOptions(id string) {
+	file, err := os.Create(c.ResultSidecarFile(id))
+	if err != nil {
+		panic(fmt.Errorf("failed to save leveldb options: %w", err))
+	}
+	defer file.Close()
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(c.LevelDB); err != nil {
+		panic(fmt.Errorf("failed to save leveldb options: %w", err))
+	}
+}
+
 func CreateDirectory(path string) string {
 	absPath, err := filepath.Abs(path)
 	if err != nil {
@@ -0,0 +1,70 @@
+package common
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+)
+
+// SaveProm は蓄積された試行データを Prometheus のテキスト交換フォーマットで path に書き出す。
+// op (例: "append", "query") と鍵 (データサイズ) をラベルとして各試行を個別のサンプルとして
+// 出力し、継続的なベンチマーク実行を時系列ダッシュボードに取り込めるようにする。
+func (s *Stats) SaveProm(path, metricPrefix, op string) {
+	file, err := os.Create(path)
+	if err != nil {
+		panic(fmt.Errorf("failed to save statistics: %w", err))
+	}
+	defer file.Close()
+
+	text := s.promText(metricPrefix, op)
+	if _, err := file.WriteString(text); err != nil {
+		panic(fmt.Errorf("failed to save statistics: %w", err))
+	}
+}
+
+// promText は SaveProm と PushProm が共有するテキスト交換フォーマットの本体を生成する。
+func (s *Stats) promText(metricPrefix, op string) string {
+	keys := make([]uint64, 0, len(s.trials))
+	for key := range s.trials {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	var buf bytes.Buffer
+	for _, key := range keys {
+		trials := s.trials[key]
+		for trial, value := range trials {
+			fmt.Fprintf(&buf, "%s_duration_ms{op=%q,n=\"%d\",trial=\"%d\"} %f\n", metricPrefix, op, key, trial, value)
+		}
+		mean, stddev, count := s.Calculate(key)
+		fmt.Fprintf(&buf, "%s_duration_ms_mean{op=%q,n=\"%d\"} %f\n", metricPrefix, op, key, mean)
+		fmt.Fprintf(&buf, "%s_duration_ms_stddev{op=%q,n=\"%d\"} %f\n", metricPrefix, op, key, stddev)
+		fmt.Fprintf(&buf, "%s_duration_ms_cv{op=%q,n=\"%d\"} %f\n", metricPrefix, op, key, stddev/mean)
+		fmt.Fprintf(&buf, "%s_duration_ms_count{op=%q,n=\"%d\"} %d\n", metricPrefix, op, key, count)
+	}
+	return buf.String()
+}
+
+// PushProm は蓄積された試行データを Prometheus Pushgateway の標準的な
+// PUT /metrics/job/<job>/session/<sessionID> エンドポイントへ送信する。job ごとの直近の
+// スナップショットだけを保持したいので PUT (置き換え) を用いる。
+func (s *Stats) PushProm(gateway, metricPrefix, op, job, sessionID string) error {
+	url := fmt.Sprintf("%s/metrics/job/%s/session/%s", gateway, job, sessionID)
+	text := s.promText(metricPrefix, op)
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewBufferString(text))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("pushgateway returned status %s", resp.Status)
+	}
+	return nil
+}
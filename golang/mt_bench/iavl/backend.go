@@ -0,0 +1,630 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	corestore "cosmossdk.io/core/store"
+	"github.com/cockroachdb/pebble"
+	"github.com/cosmos/iavl/db"
+	"github.com/syndtr/goleveldb/leveldb/filter"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	bolt "go.etcd.io/bbolt"
+
+	badger "github.com/dgraph-io/badger/v4"
+
+	"mt_bench/common"
+)
+
+func init() {
+	common.RegisterKVBackend("goleveldb", func() common.KVBackend { return &goLevelDBBackend{} })
+	common.RegisterKVBackend("pebble", func() common.KVBackend { return &pebbleBackend{} })
+	common.RegisterKVBackend("badgerdb", func() common.KVBackend { return &badgerDBBackend{} })
+	common.RegisterKVBackend("boltdb", func() common.KVBackend { return &boltDBBackend{} })
+}
+
+// goLevelDBBackend は common.KVBackend を github.com/cosmos/iavl/db の GoLevelDB
+// ラッパーに橋渡しする、もっとも標準的なバックエンドです。Tune が呼ばれなかった場合は
+// goleveldb 自身のデフォルト値がそのまま使われます。
+type goLevelDBBackend struct {
+	db   *db.GoLevelDB
+	opts *opt.Options
+}
+
+var _ common.LevelDBTunable = (*goLevelDBBackend)(nil)
+
+// Tune は --ldb-* フラグで指定されたパラメータを opt.Options に変換して保持する。
+// ブロックキャッシュ・書き込みバッファ・ブルームフィルタ・ブロックサイズ・圧縮方式は
+// いずれも goleveldb の性能を大きく左右するため、デフォルト任せにせず明示的に記録する。
+func (b *goLevelDBBackend) Tune(o common.LevelDBOptions) {
+	options := &opt.Options{
+		BlockCacheCapacity: o.CacheMB * 1024 * 1024,
+		WriteBuffer:        o.WriteBufferMB * 1024 * 1024,
+		BlockSize:          o.BlockSizeKB * 1024,
+	}
+	if o.BloomFilterBits > 0 {
+		options.Filter = filter.NewBloomFilter(o.BloomFilterBits)
+	}
+	if o.Compression {
+		options.Compression = opt.SnappyCompression
+	} else {
+		options.Compression = opt.NoCompression
+	}
+	b.opts = options
+}
+
+func (b *goLevelDBBackend) Open(path string) error {
+	var gdb *db.GoLevelDB
+	var err error
+	if b.opts != nil {
+		gdb, err = db.NewGoLevelDBWithOpts("slate", path, b.opts)
+	} else {
+		gdb, err = db.NewGoLevelDB("slate", path)
+	}
+	if err != nil {
+		return err
+	}
+	b.db = gdb
+	return nil
+}
+
+func (b *goLevelDBBackend) Close() error {
+	return b.db.Close()
+}
+
+func (b *goLevelDBBackend) DB() db.DB {
+	return b.db
+}
+
+// pebbleBackend は github.com/cockroachdb/pebble を db.DB に適合させます。goleveldb とは
+// 異なるコンパクション方式やブロックキャッシュの挙動を持つ LSM 実装との比較に使用します。
+type pebbleBackend struct {
+	pdb *pebble.DB
+}
+
+func (b *pebbleBackend) Open(path string) error {
+	pdb, err := pebble.Open(path, &pebble.Options{})
+	if err != nil {
+		return err
+	}
+	b.pdb = pdb
+	return nil
+}
+
+func (b *pebbleBackend) Close() error {
+	return b.pdb.Close()
+}
+
+func (b *pebbleBackend) DB() db.DB {
+	return &pebbleDB{db: b.pdb}
+}
+
+type pebbleDB struct {
+	db *pebble.DB
+}
+
+func (d *pebbleDB) Get(key []byte) ([]byte, error) {
+	value, closer, err := d.db.Get(key)
+	if errors.Is(err, pebble.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(value))
+	copy(out, value)
+	return out, closer.Close()
+}
+
+func (d *pebbleDB) Has(key []byte) (bool, error) {
+	value, err := d.Get(key)
+	if err != nil {
+		return false, err
+	}
+	return value != nil, nil
+}
+
+func (d *pebbleDB) Iterator(start, end []byte) (corestore.Iterator, error) {
+	it, err := d.db.NewIter(&pebble.IterOptions{LowerBound: start, UpperBound: end})
+	if err != nil {
+		return nil, err
+	}
+	return newPebbleIterator(it, start, end, false), nil
+}
+
+func (d *pebbleDB) ReverseIterator(start, end []byte) (corestore.Iterator, error) {
+	it, err := d.db.NewIter(&pebble.IterOptions{LowerBound: start, UpperBound: end})
+	if err != nil {
+		return nil, err
+	}
+	return newPebbleIterator(it, start, end, true), nil
+}
+
+func (d *pebbleDB) Close() error {
+	return d.db.Close()
+}
+
+func (d *pebbleDB) NewBatch() corestore.Batch {
+	return &pebbleBatch{db: d.db, batch: d.db.NewBatch()}
+}
+
+func (d *pebbleDB) NewBatchWithSize(size int) corestore.Batch {
+	return &pebbleBatch{db: d.db, batch: d.db.NewBatchWithSize(size)}
+}
+
+type pebbleIterator struct {
+	source    *pebble.Iterator
+	start     []byte
+	end       []byte
+	isReverse bool
+	isInvalid bool
+}
+
+func newPebbleIterator(source *pebble.Iterator, start, end []byte, isReverse bool) *pebbleIterator {
+	if isReverse {
+		source.Last()
+	} else {
+		source.First()
+	}
+	return &pebbleIterator{source: source, start: start, end: end, isReverse: isReverse}
+}
+
+func (it *pebbleIterator) Domain() ([]byte, []byte) {
+	return it.start, it.end
+}
+
+func (it *pebbleIterator) Valid() bool {
+	if it.isInvalid {
+		return false
+	}
+	if err := it.source.Error(); err != nil {
+		it.isInvalid = true
+		return false
+	}
+	if !it.source.Valid() {
+		it.isInvalid = true
+		return false
+	}
+	key := it.source.Key()
+	if it.isReverse {
+		if it.start != nil && bytes.Compare(key, it.start) < 0 {
+			it.isInvalid = true
+			return false
+		}
+	} else if it.end != nil && bytes.Compare(key, it.end) >= 0 {
+		it.isInvalid = true
+		return false
+	}
+	return true
+}
+
+func (it *pebbleIterator) Key() []byte {
+	out := make([]byte, len(it.source.Key()))
+	copy(out, it.source.Key())
+	return out
+}
+
+func (it *pebbleIterator) Value() []byte {
+	out := make([]byte, len(it.source.Value()))
+	copy(out, it.source.Value())
+	return out
+}
+
+func (it *pebbleIterator) Next() {
+	if it.isReverse {
+		it.source.Prev()
+	} else {
+		it.source.Next()
+	}
+}
+
+func (it *pebbleIterator) Error() error {
+	return it.source.Error()
+}
+
+func (it *pebbleIterator) Close() error {
+	return it.source.Close()
+}
+
+type pebbleBatch struct {
+	db    *pebble.DB
+	batch *pebble.Batch
+}
+
+func (b *pebbleBatch) Set(key, value []byte) error {
+	return b.batch.Set(key, value, nil)
+}
+
+func (b *pebbleBatch) Delete(key []byte) error {
+	return b.batch.Delete(key, nil)
+}
+
+func (b *pebbleBatch) Write() error {
+	return b.db.Apply(b.batch, pebble.NoSync)
+}
+
+func (b *pebbleBatch) WriteSync() error {
+	return b.db.Apply(b.batch, pebble.Sync)
+}
+
+func (b *pebbleBatch) Close() error {
+	return b.batch.Close()
+}
+
+func (b *pebbleBatch) GetByteSize() (int, error) {
+	return int(b.batch.Len()), nil
+}
+
+// badgerDBBackend は github.com/dgraph-io/badger を db.DB に適合させます。LSM 構造は
+// pebble/goleveldb と同系統ですが、値を別ログ (value log) に分離する設計のため書き込み
+// 増幅の傾向が異なり、IAVL のノードサイズ分布との相性を比較する目的で加えています。
+type badgerDBBackend struct {
+	bdb *badger.DB
+}
+
+func (b *badgerDBBackend) Open(path string) error {
+	bdb, err := badger.Open(badger.DefaultOptions(path).WithLogger(nil))
+	if err != nil {
+		return err
+	}
+	b.bdb = bdb
+	return nil
+}
+
+func (b *badgerDBBackend) Close() error {
+	return b.bdb.Close()
+}
+
+func (b *badgerDBBackend) DB() db.DB {
+	return &badgerDB{db: b.bdb}
+}
+
+type badgerDB struct {
+	db *badger.DB
+}
+
+func (d *badgerDB) Get(key []byte) ([]byte, error) {
+	var out []byte
+	err := d.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(key)
+		if errors.Is(err, badger.ErrKeyNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		out, err = item.ValueCopy(nil)
+		return err
+	})
+	return out, err
+}
+
+func (d *badgerDB) Has(key []byte) (bool, error) {
+	value, err := d.Get(key)
+	if err != nil {
+		return false, err
+	}
+	return value != nil, nil
+}
+
+func (d *badgerDB) Iterator(start, end []byte) (corestore.Iterator, error) {
+	txn := d.db.NewTransaction(false)
+	opts := badger.DefaultIteratorOptions
+	it := txn.NewIterator(opts)
+	it.Seek(start)
+	return newBadgerIterator(txn, it, start, end, false), nil
+}
+
+func (d *badgerDB) ReverseIterator(start, end []byte) (corestore.Iterator, error) {
+	txn := d.db.NewTransaction(false)
+	opts := badger.DefaultIteratorOptions
+	opts.Reverse = true
+	it := txn.NewIterator(opts)
+	if end != nil {
+		it.Seek(end)
+	} else {
+		it.Rewind()
+	}
+	return newBadgerIterator(txn, it, start, end, true), nil
+}
+
+func (d *badgerDB) Close() error {
+	return nil
+}
+
+func (d *badgerDB) NewBatch() corestore.Batch {
+	return &badgerBatch{db: d.db, wb: d.db.NewWriteBatch()}
+}
+
+func (d *badgerDB) NewBatchWithSize(size int) corestore.Batch {
+	return d.NewBatch()
+}
+
+type badgerIterator struct {
+	txn       *badger.Txn
+	source    *badger.Iterator
+	start     []byte
+	end       []byte
+	isReverse bool
+}
+
+func newBadgerIterator(txn *badger.Txn, source *badger.Iterator, start, end []byte, isReverse bool) *badgerIterator {
+	return &badgerIterator{txn: txn, source: source, start: start, end: end, isReverse: isReverse}
+}
+
+func (it *badgerIterator) Domain() ([]byte, []byte) {
+	return it.start, it.end
+}
+
+func (it *badgerIterator) Valid() bool {
+	if !it.source.Valid() {
+		return false
+	}
+	key := it.source.Item().Key()
+	if it.isReverse {
+		if it.start != nil && bytes.Compare(key, it.start) < 0 {
+			return false
+		}
+	} else if it.end != nil && bytes.Compare(key, it.end) >= 0 {
+		return false
+	}
+	return true
+}
+
+func (it *badgerIterator) Key() []byte {
+	out := make([]byte, len(it.source.Item().Key()))
+	copy(out, it.source.Item().Key())
+	return out
+}
+
+func (it *badgerIterator) Value() []byte {
+	value, err := it.source.Item().ValueCopy(nil)
+	if err != nil {
+		panic(fmt.Errorf("failed to copy badger value: %v", err))
+	}
+	return value
+}
+
+func (it *badgerIterator) Next() {
+	it.source.Next()
+}
+
+func (it *badgerIterator) Error() error {
+	return nil
+}
+
+func (it *badgerIterator) Close() error {
+	it.source.Close()
+	it.txn.Discard()
+	return nil
+}
+
+type badgerBatch struct {
+	db *badger.DB
+	wb *badger.WriteBatch
+}
+
+func (b *badgerBatch) Set(key, value []byte) error {
+	return b.wb.Set(key, value)
+}
+
+func (b *badgerBatch) Delete(key []byte) error {
+	return b.wb.Delete(key)
+}
+
+func (b *badgerBatch) Write() error {
+	return b.wb.Flush()
+}
+
+func (b *badgerBatch) WriteSync() error {
+	return b.wb.Flush()
+}
+
+func (b *badgerBatch) Close() error {
+	b.wb.Cancel()
+	return nil
+}
+
+func (b *badgerBatch) GetByteSize() (int, error) {
+	return 0, nil
+}
+
+// boltDBBackend は go.etcd.io/bbolt を db.DB に適合させます。LSM 系の 3 エンジンとは異なり
+// B+Tree ベースの単一ファイル実装であるため、書き込み増幅よりもページ分割コストが支配的に
+// なる点を比較する目的で加えています。
+type boltDBBackend struct {
+	bdb *bolt.DB
+}
+
+var boltBucketName = []byte("slate")
+
+func (b *boltDBBackend) Open(path string) error {
+	bdb, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		return err
+	}
+	if err := bdb.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltBucketName)
+		return err
+	}); err != nil {
+		return err
+	}
+	b.bdb = bdb
+	return nil
+}
+
+func (b *boltDBBackend) Close() error {
+	return b.bdb.Close()
+}
+
+func (b *boltDBBackend) DB() db.DB {
+	return &boltDBWrapper{db: b.bdb}
+}
+
+type boltDBWrapper struct {
+	db *bolt.DB
+}
+
+func (d *boltDBWrapper) Get(key []byte) ([]byte, error) {
+	var out []byte
+	err := d.db.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(boltBucketName).Get(key)
+		if value != nil {
+			out = append([]byte(nil), value...)
+		}
+		return nil
+	})
+	return out, err
+}
+
+func (d *boltDBWrapper) Has(key []byte) (bool, error) {
+	value, err := d.Get(key)
+	if err != nil {
+		return false, err
+	}
+	return value != nil, nil
+}
+
+func (d *boltDBWrapper) Iterator(start, end []byte) (corestore.Iterator, error) {
+	tx, err := d.db.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	cursor := tx.Bucket(boltBucketName).Cursor()
+	return newBoltIterator(tx, cursor, start, end, false), nil
+}
+
+func (d *boltDBWrapper) ReverseIterator(start, end []byte) (corestore.Iterator, error) {
+	tx, err := d.db.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	cursor := tx.Bucket(boltBucketName).Cursor()
+	return newBoltIterator(tx, cursor, start, end, true), nil
+}
+
+func (d *boltDBWrapper) Close() error {
+	return nil
+}
+
+func (d *boltDBWrapper) NewBatch() corestore.Batch {
+	return &boltBatch{db: d.db}
+}
+
+func (d *boltDBWrapper) NewBatchWithSize(size int) corestore.Batch {
+	return d.NewBatch()
+}
+
+type boltIterator struct {
+	tx        *bolt.Tx
+	cursor    *bolt.Cursor
+	start     []byte
+	end       []byte
+	isReverse bool
+	key       []byte
+	value     []byte
+}
+
+func newBoltIterator(tx *bolt.Tx, cursor *bolt.Cursor, start, end []byte, isReverse bool) *boltIterator {
+	it := &boltIterator{tx: tx, cursor: cursor, start: start, end: end, isReverse: isReverse}
+	if isReverse {
+		if end != nil {
+			it.key, it.value = cursor.Seek(end)
+			if it.key == nil {
+				it.key, it.value = cursor.Last()
+			} else {
+				it.key, it.value = cursor.Prev()
+			}
+		} else {
+			it.key, it.value = cursor.Last()
+		}
+	} else {
+		it.key, it.value = cursor.Seek(start)
+	}
+	return it
+}
+
+func (it *boltIterator) Domain() ([]byte, []byte) {
+	return it.start, it.end
+}
+
+func (it *boltIterator) Valid() bool {
+	if it.key == nil {
+		return false
+	}
+	if it.isReverse {
+		if it.start != nil && bytes.Compare(it.key, it.start) < 0 {
+			return false
+		}
+	} else if it.end != nil && bytes.Compare(it.key, it.end) >= 0 {
+		return false
+	}
+	return true
+}
+
+func (it *boltIterator) Key() []byte {
+	return append([]byte(nil), it.key...)
+}
+
+func (it *boltIterator) Value() []byte {
+	return append([]byte(nil), it.value...)
+}
+
+func (it *boltIterator) Next() {
+	if it.isReverse {
+		it.key, it.value = it.cursor.Prev()
+	} else {
+		it.key, it.value = it.cursor.Next()
+	}
+}
+
+func (it *boltIterator) Error() error {
+	return nil
+}
+
+func (it *boltIterator) Close() error {
+	return it.tx.Rollback()
+}
+
+type boltBatch struct {
+	db  *bolt.DB
+	ops []func(*bolt.Bucket) error
+}
+
+func (b *boltBatch) Set(key, value []byte) error {
+	k, v := append([]byte(nil), key...), append([]byte(nil), value...)
+	b.ops = append(b.ops, func(bucket *bolt.Bucket) error { return bucket.Put(k, v) })
+	return nil
+}
+
+func (b *boltBatch) Delete(key []byte) error {
+	k := append([]byte(nil), key...)
+	b.ops = append(b.ops, func(bucket *bolt.Bucket) error { return bucket.Delete(k) })
+	return nil
+}
+
+func (b *boltBatch) Write() error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(boltBucketName)
+		for _, op := range b.ops {
+			if err := op(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (b *boltBatch) WriteSync() error {
+	return b.Write()
+}
+
+func (b *boltBatch) Close() error {
+	b.ops = nil
+	return nil
+}
+
+func (b *boltBatch) GetByteSize() (int, error) {
+	return len(b.ops), nil
+}
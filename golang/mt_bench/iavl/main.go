@@ -0,0 +1,216 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/cosmos/iavl"
+
+	"mt_bench/common"
+)
+
+// versionForKey は、commitBatch 件の Set ごとに 1 回 SaveVersion するという規則のもとで
+// key 番目に挿入された値がどのバージョンにコミットされたかを返す。キーは 1 から順に詰めて
+// 挿入されるため、実際にどのキーがどのバージョンに属したかを別途記録しなくても算出できる。
+func versionForKey(key, commitBatch uint64) int64 {
+	return int64((key-1)/commitBatch + 1)
+}
+
+// 指定されたディレクトリに backend 上で IAVL を作成します。measureAppend/measureQuery は
+// ともに同じバックエンド名とチューニングパラメータを束縛したクロージャとして生成され、
+// --backend で選んだストレージエンジンの違いが木構造そのもののコストから切り分けて
+// 観測できるようにします。commitBatch > 1 の場合、SaveVersion は commitBatch 件の Set
+// ごとにしか呼ばれず、実際の Cosmos SDK のブロックあたり複数書き込みに近い負荷になります。
+func measureAppendWith(backend string, ldbOpts common.LevelDBOptions, commitBatch uint64) func(string, uint64) (time.Duration, uint64) {
+	return func(path string, n uint64) (time.Duration, uint64) {
+		kv := common.NewKVBackend(backend)
+		if tunable, ok := kv.(common.LevelDBTunable); ok {
+			tunable.Tune(ldbOpts)
+		}
+		if err := kv.Open(path); err != nil {
+			panic(fmt.Errorf("failed to open %s: %v", backend, err))
+		}
+		defer kv.Close()
+		tree := iavl.NewMutableTree(kv.DB(), 0, false, iavl.NewNopLogger())
+		defer tree.Close()
+		if _, err := tree.Load(); err != nil {
+			panic(fmt.Errorf("failed to load tree: %v", err))
+		}
+
+		runtime.GC()
+		start := time.Now()
+		for uint64(tree.Size()) < n {
+			i := uint64(tree.Size() + 1)
+			_, err := tree.Set(common.IntToKey(i), common.IntToValue(i))
+			if err != nil {
+				panic(fmt.Errorf("failed to update iavl database: %v", err))
+			}
+			if i%commitBatch == 0 || i == n {
+				tree.Hash()
+				_, _, err = tree.SaveVersion()
+				if err != nil {
+					panic(fmt.Errorf("failed to version iavl database: %v", err))
+				}
+			}
+		}
+		duration := time.Since(start)
+
+		return duration, uint64(common.FileOrDirectorySize(path))
+	}
+}
+
+// 既存のルートハッシュから backend 上の IAVL をロードし、値を取得します。commitBatch は
+// measureAppendWith に渡したものと同じ値でなければならず、キー番号から versionForKey で
+// 実際にコミットされたバージョンを逆算します。
+func measureQueryWith(backend string, ldbOpts common.LevelDBOptions, commitBatch uint64) func(string, []uint64) map[uint64]time.Duration {
+	return func(path string, is []uint64) map[uint64]time.Duration {
+		kv := common.NewKVBackend(backend)
+		if tunable, ok := kv.(common.LevelDBTunable); ok {
+			tunable.Tune(ldbOpts)
+		}
+		if err := kv.Open(path); err != nil {
+			panic(fmt.Errorf("failed to open %s: %v", backend, err))
+		}
+		defer kv.Close()
+		tree := iavl.NewMutableTree(kv.DB(), 0, false, iavl.NewNopLogger())
+		defer tree.Close()
+		if _, err := tree.Load(); err != nil {
+			panic(fmt.Errorf("failed to load tree: %v", err))
+		}
+
+		result := make(map[uint64]time.Duration)
+		for _, i := range is {
+			runtime.GC()
+			start := time.Now()
+			bytes, err := tree.GetVersioned(common.IntToKey(i), versionForKey(i, commitBatch))
+			if err != nil {
+				panic(err)
+			}
+			duration := time.Since(start)
+			result[i] = duration
+			value := common.ValueToInt(bytes)
+			if value != common.Splitmix64(i) {
+				panic(fmt.Errorf("The value read for i=%d is incorrect: %d != %d", i, common.Splitmix64(i), value))
+			}
+		}
+		return result
+	}
+}
+
+// 指定された操作列 (Read/Insert/Update/Delete) を backend 上の IAVL に対して順番に発行し、
+// 各操作ごとのレイテンシを ops と同じ順序で返す。YCSB ライクな混在ワークロードのベンチマーク
+// から common.BenchmarkMixed のコールバックとして渡される。measureAppendWith と同様、
+// SaveVersion は commitBatch 件の Insert/Update/Delete ごとにしか呼ばれない
+// (末尾に端数が残っていれば最後に 1 回だけ flush する)。これは OpRead が使う
+// versionForKey(key, commitBatch) の前提 (キーは commitBatch 件ごとにコミットされている)
+// と整合させるため。
+func measureOpWith(backend string, ldbOpts common.LevelDBOptions, commitBatch uint64) func(string, []common.Op) []time.Duration {
+	return func(path string, ops []common.Op) []time.Duration {
+		kv := common.NewKVBackend(backend)
+		if tunable, ok := kv.(common.LevelDBTunable); ok {
+			tunable.Tune(ldbOpts)
+		}
+		if err := kv.Open(path); err != nil {
+			panic(fmt.Errorf("failed to open %s: %v", backend, err))
+		}
+		defer kv.Close()
+		tree := iavl.NewMutableTree(kv.DB(), 0, false, iavl.NewNopLogger())
+		defer tree.Close()
+		if _, err := tree.Load(); err != nil {
+			panic(fmt.Errorf("failed to load tree: %v", err))
+		}
+
+		durations := make([]time.Duration, len(ops))
+		var mutations uint64
+		for i, op := range ops {
+			start := time.Now()
+			switch op.Type {
+			case common.OpRead:
+				bytes, err := tree.GetVersioned(common.IntToKey(op.Key), versionForKey(op.Key, commitBatch))
+				if err != nil {
+					panic(fmt.Errorf("failed to read key %d: %v", op.Key, err))
+				}
+				if value := common.ValueToInt(bytes); value != common.Splitmix64(op.Key) {
+					panic(fmt.Errorf("the value read for key %d is incorrect: %d != %d", op.Key, common.Splitmix64(op.Key), value))
+				}
+			case common.OpInsert, common.OpUpdate:
+				if _, err := tree.Set(common.IntToKey(op.Key), common.IntToValue(op.Key)); err != nil {
+					panic(fmt.Errorf("failed to set key %d: %v", op.Key, err))
+				}
+				mutations++
+				if mutations%commitBatch == 0 {
+					if _, _, err := tree.SaveVersion(); err != nil {
+						panic(fmt.Errorf("failed to version iavl database: %v", err))
+					}
+				}
+			case common.OpDelete:
+				if _, _, err := tree.Remove(common.IntToKey(op.Key)); err != nil {
+					panic(fmt.Errorf("failed to remove key %d: %v", op.Key, err))
+				}
+				mutations++
+				if mutations%commitBatch == 0 {
+					if _, _, err := tree.SaveVersion(); err != nil {
+						panic(fmt.Errorf("failed to version iavl database: %v", err))
+					}
+				}
+			}
+			durations[i] = time.Since(start)
+		}
+		if mutations%commitBatch != 0 {
+			if _, _, err := tree.SaveVersion(); err != nil {
+				panic(fmt.Errorf("failed to version iavl database: %v", err))
+			}
+		}
+		return durations
+	}
+}
+
+func main() {
+	config := common.ParseCommandLine([]string{
+		"query-iavl-goleveldb",
+		"append-iavl-goleveldb",
+		"query-iavl-pebble",
+		"append-iavl-pebble",
+		"query-iavl-badgerdb",
+		"append-iavl-badgerdb",
+		"query-iavl-boltdb",
+		"append-iavl-boltdb",
+		"mixed-iavl-goleveldb-a",
+		"mixed-iavl-goleveldb-b",
+		"mixed-iavl-goleveldb-c",
+		"mixed-iavl-goleveldb-d",
+		"mixed-iavl-goleveldb-f",
+	}, "IAVL+ Performance Benchmark Tool", `IAVL+ Performance Benchmark Tool
+
+  This tool performs comprehensive performance benchmarking of IAVL+ (Immutable
+  AVL+) trees. The underlying KV storage engine is selected with --backend so
+  the tree's own complexity can be isolated from that of the storage it sits
+  on. It measures both time and space complexity for append operations and
+  query performance across different data sizes.
+`)
+	common.PrintSystemInfo(fmt.Sprintf("Cosmos IAVL+ Benchmark (%s-based)", config.Backend), fmt.Sprintf("File (%s)", config.Backend), config)
+
+	id := fmt.Sprintf("iavl-%s", config.Backend)
+	if config.CommitBatch > 1 {
+		id = fmt.Sprintf("%s-batch%d", id, config.CommitBatch)
+	}
+	measureAppend := measureAppendWith(config.Backend, config.LevelDB, config.CommitBatch)
+	measureQuery := measureQueryWith(config.Backend, config.LevelDB, config.CommitBatch)
+
+	if config.Workload != "" {
+		workload, ok := common.YCSBWorkloads[config.Workload]
+		if !ok {
+			panic(fmt.Errorf("unknown workload: %s", config.Workload))
+		}
+		measureOp := measureOpWith(config.Backend, config.LevelDB, config.CommitBatch)
+		common.BenchmarkMixed(config, fmt.Sprintf("mixed-%s-%s", id, config.Workload), workload, 1000, measureAppend, measureOp)
+		return
+	}
+
+	common.BenchmarkQuery(config, "get-"+id, measureAppend, measureQuery)
+	common.BenchmarkAppend(config, "append-"+id, "volume-"+id, measureAppend)
+	if config.Backend == "goleveldb" {
+		config.SaveLevelDBOptions(id)
+	}
+}
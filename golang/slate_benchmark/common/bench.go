@@ -0,0 +1,794 @@
+package common
+
+import (
+	"encoding/binary"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// ベンチマーク設定
+const (
+	MaxTrials         = 1000 // 最大試行回数
+	MinTrials         = 5    // 最小試行回数
+	CVThreshold       = 0.05 // 標準偏差/平均値のしきい値 (5%) -- --legacy-cv 指定時のみ使用
+	CIThreshold       = 0.10 // ブートストラップ信頼区間の相対幅 (ci_hi-ci_lo)/mean のしきい値
+	BootstrapResample = 1000 // ブートストラップ法で平均を再計算する回数
+	AppendDivision    = 10   // Append 測定での分割数
+	QueryDivision     = 100  // Query 測定での分割数
+	MaxDuration       = 10 * time.Minute
+	DefaultResultDir  = "." // デフォルトの結果出力ディレクトリ
+)
+
+type CUT interface {
+	Open()
+	Close()
+	MeasureAppend(uint64) (time.Duration, uint64)
+	MeasureAppendBatched(n, batchSize uint64) (time.Duration, uint64)
+	MeasureGets([]uint64) map[uint64]time.Duration
+	MeasureProof([]uint64) map[uint64]ProofResult
+	// MeasureConcurrentGets fetches every key in is using workers goroutines fed by a
+	// shared channel, and returns the per-key latency along with the achieved aggregate
+	// throughput in ops/sec. Implementations must use a read handle that is safe to share
+	// across goroutines (e.g. an immutable snapshot), since the backend's primary
+	// read/write handle is not generally concurrency-safe for reads during writes.
+	MeasureConcurrentGets(is []uint64, workers int) (map[uint64]time.Duration, float64)
+}
+
+// 既定のバッチサイズ掃引。commit-per-op を前提にした各 CUT の実装は、コミット粒度に
+// よって支配的なコストが違うため、固定 DataSize のもとでこの掃引を行い比較する。
+var DefaultBatchSizes = []uint64{1, 10, 100, 1000, 10000}
+
+// ErrProofUnsupported は、鍵の証明を生成できない CUT 実装 (素の KV ストアなど) が
+// MeasureProof から返すセンチネルエラー。BenchmarkProof はこれを検出してベンチマーク
+// 自体をスキップする。
+var ErrProofUnsupported = errors.New("this CUT backend does not support Merkle proof generation")
+
+// ProofResult は1キー分の証明生成・検証の計測結果。Err が ErrProofUnsupported の場合は
+// そのバックエンドが証明に対応していないことを示す。
+type ProofResult struct {
+	GenNanos    time.Duration
+	VerifyNanos time.Duration
+	ProofBytes  int
+	Err         error
+}
+
+// コマンドライン引数
+type Config struct {
+	DataSize  uint64
+	WorkDir   string
+	ResultDir string
+	Timeout   time.Duration
+	SessionID string
+	LegacyCV  bool
+}
+
+// Append 性能のベンチマーク
+func BenchmarkAppend(
+	config *Config,
+	append_id, volume_id string,
+	cut CUT,
+) {
+	fmt.Println(time.Now().Format("2006-01-02 15:04:05 MST"))
+	fmt.Printf("=== Append Benchmark (%s) ===\n", append_id)
+
+	timer := NewExpirationTimer(config.Timeout, 10, MaxTrials, 10)
+	timer.HeadingMS()
+
+	ns := Linspace(1, config.DataSize, AppendDivision)
+	timeComplexity := NewStats()
+	spaceComplexity := NewStats()
+	for i := 0; i < MaxTrials; i++ {
+
+		config.RemoveDatabase(append_id)
+		cut.Close()
+		cut.Open()
+		var cumTime time.Duration
+		for _, n := range ns {
+			elapse, space := cut.MeasureAppend(n)
+			cumTime += elapse
+			timeComplexity.Add(n, float64(cumTime.Nanoseconds())/1000.0/1000.0)
+			if i == 0 {
+				spaceComplexity.Add(n, float64(space))
+			}
+		}
+
+		if i+1 > MinTrials && len(FilterConvergent(ns, timeComplexity, config)) == 0 {
+			mean, stddev, _ := timeComplexity.Calculate(config.DataSize)
+			timer.SummaryMS(config.DataSize, mean, stddev)
+			break
+		}
+		if timer.Expired() {
+			mean, stddev, _ := timeComplexity.Calculate(config.DataSize)
+			timer.SummaryMS(config.DataSize, mean, stddev)
+			fmt.Println("** TIMED OUT **")
+			break
+		}
+		if timer.CarriedOut(1) {
+			mean, stddev, _ := timeComplexity.Calculate(config.DataSize)
+			timer.SummaryMS(config.DataSize, mean, stddev)
+		}
+	}
+
+	timeComplexity.SaveSummary(config.ResultFile(append_id), "SIZE")
+	spaceComplexity.Save(config.ResultFile(volume_id), "SIZE", "BYTES")
+}
+
+// Append 性能のバッチサイズ掃引ベンチマーク。固定の DataSize に対して commit (SaveVersion /
+// DOLT_COMMIT 相当) の粒度を変えながら計測することで、commit-per-op を前提にした単純な比較では
+// 見えないコミット頻度と性能のトレードオフを可視化する。
+func BenchmarkAppendBatch(
+	config *Config,
+	id string,
+	cut CUT,
+	batchSizes []uint64,
+) {
+	fmt.Println(time.Now().Format("2006-01-02 15:04:05 MST"))
+	fmt.Printf("=== Append Batch-Size Sweep Benchmark (%s) ===\n", id)
+
+	timer := NewExpirationTimer(config.Timeout, 10, MaxTrials, 10)
+	timer.HeadingMS()
+
+	timeComplexity := NewStats()
+	for i := 0; i < MaxTrials; i++ {
+		for _, batchSize := range batchSizes {
+			config.RemoveDatabase(id)
+			cut.Close()
+			cut.Open()
+			elapse, _ := cut.MeasureAppendBatched(config.DataSize, batchSize)
+			timeComplexity.Add(batchSize, float64(elapse.Nanoseconds())/1000.0/1000.0)
+		}
+
+		if i+1 > MinTrials && len(FilterConvergent(batchSizes, timeComplexity, config)) == 0 {
+			mean, stddev, _ := timeComplexity.Calculate(batchSizes[len(batchSizes)-1])
+			timer.SummaryMS(batchSizes[len(batchSizes)-1], mean, stddev)
+			break
+		}
+		if timer.Expired() {
+			mean, stddev, _ := timeComplexity.Calculate(batchSizes[len(batchSizes)-1])
+			timer.SummaryMS(batchSizes[len(batchSizes)-1], mean, stddev)
+			fmt.Println("** TIMED OUT **")
+			break
+		}
+		if timer.CarriedOut(1) {
+			mean, stddev, _ := timeComplexity.Calculate(batchSizes[len(batchSizes)-1])
+			timer.SummaryMS(batchSizes[len(batchSizes)-1], mean, stddev)
+		}
+	}
+	config.RemoveDatabase(id)
+
+	timeComplexity.SaveSummary(config.ResultFile(id), "BATCH_SIZE")
+}
+
+// Get 性能のベンチマーク
+func BenchmarkGet(
+	config *Config,
+	query_id string,
+	cut CUT,
+) {
+	fmt.Println(time.Now().Format("2006-01-02 15:04:05 MST"))
+	fmt.Printf("=== Get Benchmark (%s) ===\n", query_id)
+
+	// データベースを作成
+	fmt.Printf("Preparing database with %d entries: ", config.DataSize)
+	config.RemoveDatabase(query_id)
+	t0 := time.Now()
+	cut.Open()
+	cut.MeasureAppend(config.DataSize)
+	tm := time.Since(t0)
+	fmt.Printf("done: %.3f [msec]\n", float64(tm.Nanoseconds())/1000.0/1000.0)
+
+	distances := Logspace(1, config.DataSize, QueryDivision)
+	is := make([]uint64, len(distances))
+	for i, distance := range distances {
+		is[i] = config.DataSize - distance + 1
+	}
+
+	timer := NewExpirationTimer(config.Timeout, 10, MaxTrials, 10)
+	timer.HeadingMaxCV()
+
+	rand.Seed(time.Now().UnixNano())
+	timeComplexity := NewStats()
+	for i := 0; i < MaxTrials; i++ {
+		rand.Shuffle(len(is), func(i, j int) {
+			is[i], is[j] = is[j], is[i]
+		})
+		result := cut.MeasureGets(is)
+		for j, duration := range result {
+			timeComplexity.Add(j, float64(duration.Nanoseconds())/1000.0/1000.0)
+		}
+
+		if i+1 >= MinTrials {
+			is = FilterConvergent(is, timeComplexity, config)
+			if len(is) == 0 {
+				timer.SummaryMaxCV(config.DataSize, timeComplexity.MaxRelative())
+				break
+			}
+		}
+		if timer.Expired() {
+			timer.SummaryMaxCV(config.DataSize, timeComplexity.MaxRelative())
+			fmt.Println("** TIMED OUT **")
+			break
+		}
+		if timer.CarriedOut(1) {
+			timer.SummaryMaxCV(config.DataSize, timeComplexity.MaxRelative())
+		}
+	}
+
+	timeComplexity.SaveSummary(config.ResultFile(query_id), "SIZE")
+}
+
+// 並行リーダーによる Get 性能のベンチマーク。固定のデータサイズに対してワーカー数を
+// 1 から runtime.NumCPU()*2 まで掃引し、ワーカー数ごとに達成したスループット (ops/sec) と
+// p50/p99 のレイテンシを1行とした CSV を出力する。
+func BenchmarkConcurrentGet(
+	config *Config,
+	id string,
+	cut CUT,
+) {
+	fmt.Println(time.Now().Format("2006-01-02 15:04:05 MST"))
+	fmt.Printf("=== Concurrent Get Benchmark (%s) ===\n", id)
+
+	fmt.Printf("Preparing database with %d entries: ", config.DataSize)
+	config.RemoveDatabase(id)
+	t0 := time.Now()
+	cut.Open()
+	cut.MeasureAppend(config.DataSize)
+	tm := time.Since(t0)
+	fmt.Printf("done: %.3f [msec]\n", float64(tm.Nanoseconds())/1000.0/1000.0)
+
+	distances := Logspace(1, config.DataSize, QueryDivision)
+	is := make([]uint64, len(distances))
+	for i, distance := range distances {
+		is[i] = config.DataSize - distance + 1
+	}
+
+	maxWorkers := runtime.NumCPU() * 2
+	throughput := NewStats()
+	latency := NewStats()
+	for workers := 1; workers <= maxWorkers; workers++ {
+		fmt.Printf("--- workers: %d ---\n", workers)
+
+		timer := NewExpirationTimer(config.Timeout, 10, MaxTrials, 10)
+		timer.HeadingMaxCV()
+
+		rand.Seed(time.Now().UnixNano())
+		w := uint64(workers)
+		for i := 0; i < MaxTrials; i++ {
+			rand.Shuffle(len(is), func(i, j int) {
+				is[i], is[j] = is[j], is[i]
+			})
+			result, ops := cut.MeasureConcurrentGets(is, workers)
+			throughput.Add(w, ops)
+			for _, duration := range result {
+				latency.Add(w, float64(duration.Nanoseconds())/1000.0/1000.0)
+			}
+
+			if i+1 >= MinTrials {
+				if isConvergent(w, latency, config) {
+					timer.SummaryMaxCV(config.DataSize, latency.MaxRelative())
+					break
+				}
+			}
+			if timer.Expired() {
+				timer.SummaryMaxCV(config.DataSize, latency.MaxRelative())
+				fmt.Println("** TIMED OUT **")
+				break
+			}
+			if timer.CarriedOut(1) {
+				timer.SummaryMaxCV(config.DataSize, latency.MaxRelative())
+			}
+		}
+	}
+
+	throughput.SaveSummary(config.ResultFile(fmt.Sprintf("%s-throughput", id)), "WORKERS")
+	latency.SaveSummary(config.ResultFile(fmt.Sprintf("%s-latency", id)), "WORKERS")
+}
+
+// isConvergent は、単一のキー (ここではワーカー数) に対する収束判定を FilterConvergent と
+// 同じ基準で行うための小さなヘルパー。
+func isConvergent(key uint64, s *Stats, config *Config) bool {
+	if config.LegacyCV {
+		return s.IsCVSufficient(key, CVThreshold)
+	}
+	return s.IsCISufficient(key, CIThreshold)
+}
+
+// Merkle 証明の生成・検証性能のベンチマーク。BenchmarkGet と同様に対数的に選ばれた距離の
+// キーで CV が収束するまで試行を繰り返し、証明生成時間・検証時間・証明サイズを木のサイズ
+// ごとの CSV として出力する。cut が証明に対応していない場合は何も書き出さずに戻る。
+func BenchmarkProof(
+	config *Config,
+	genTimeID, verifyTimeID, sizeID string,
+	cut CUT,
+) {
+	fmt.Println(time.Now().Format("2006-01-02 15:04:05 MST"))
+	fmt.Printf("=== Proof Benchmark (%s) ===\n", genTimeID)
+
+	// データベースを作成
+	fmt.Printf("Preparing database with %d entries: ", config.DataSize)
+	config.RemoveDatabase(genTimeID)
+	t0 := time.Now()
+	cut.Open()
+	cut.MeasureAppend(config.DataSize)
+	tm := time.Since(t0)
+	fmt.Printf("done: %.3f [msec]\n", float64(tm.Nanoseconds())/1000.0/1000.0)
+
+	distances := Logspace(1, config.DataSize, QueryDivision)
+	is := make([]uint64, len(distances))
+	for i, distance := range distances {
+		is[i] = config.DataSize - distance + 1
+	}
+
+	if probe := cut.MeasureProof(is[:1]); probe[is[0]].Err != nil && errors.Is(probe[is[0]].Err, ErrProofUnsupported) {
+		fmt.Printf("%s does not support Merkle proofs, skipping\n", genTimeID)
+		return
+	}
+
+	timer := NewExpirationTimer(config.Timeout, 10, MaxTrials, 10)
+	timer.HeadingMaxCV()
+
+	rand.Seed(time.Now().UnixNano())
+	genTime := NewStats()
+	verifyTime := NewStats()
+	proofSize := NewStats()
+	for i := 0; i < MaxTrials; i++ {
+		rand.Shuffle(len(is), func(i, j int) {
+			is[i], is[j] = is[j], is[i]
+		})
+		results := cut.MeasureProof(is)
+		for j, result := range results {
+			if result.Err != nil {
+				panic(fmt.Errorf("failed to generate proof for i=%d: %v", j, result.Err))
+			}
+			genTime.Add(j, float64(result.GenNanos.Nanoseconds())/1000.0/1000.0)
+			verifyTime.Add(j, float64(result.VerifyNanos.Nanoseconds())/1000.0/1000.0)
+			if i == 0 {
+				proofSize.Add(j, float64(result.ProofBytes))
+			}
+		}
+
+		if i+1 >= MinTrials {
+			is = FilterConvergent(is, genTime, config)
+			if len(is) == 0 {
+				timer.SummaryMaxCV(config.DataSize, genTime.MaxRelative())
+				break
+			}
+		}
+		if timer.Expired() {
+			timer.SummaryMaxCV(config.DataSize, genTime.MaxRelative())
+			fmt.Println("** TIMED OUT **")
+			break
+		}
+		if timer.CarriedOut(1) {
+			timer.SummaryMaxCV(config.DataSize, genTime.MaxRelative())
+		}
+	}
+
+	genTime.SaveSummary(config.ResultFile(genTimeID), "SIZE")
+	verifyTime.SaveSummary(config.ResultFile(verifyTimeID), "SIZE")
+	proofSize.Save(config.ResultFile(sizeID), "SIZE", "BYTES")
+}
+
+// コマンドライン引数の解析
+func ParseCommandLine(names []string, short, long string) *Config {
+	config := &Config{
+		DataSize:  256,
+		WorkDir:   "",
+		ResultDir: "",
+		Timeout:   10 * time.Minute,
+		SessionID: "",
+	}
+
+	rootCmd := &cobra.Command{
+		Use:   fmt.Sprintf("%s [data-size]", os.Args[0]),
+		Short: short,
+		Long:  long,
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if len(args) == 1 {
+				num, err := strconv.ParseUint(args[0], 10, 64)
+				if err != nil {
+					fmt.Printf("変換エラー: %v\n", err)
+					return
+				}
+				config.DataSize = num
+			}
+		},
+	}
+	flags := rootCmd.Flags()
+	workDirFlag := flags.StringP("dir", "d", os.TempDir(), "Database directory used for benchmarking")
+	resultDirFlag := flags.StringP("output", "o", DefaultResultDir, "Directory to save result CSV files")
+	timeoutFlag := flags.Duration("timeout", 10*time.Minute, "Benchmark timeout (e.g., 30s, 5m)")
+	sessionIdFlag := flags.StringP("session", "s", time.Now().Format("20060102150405"), "Session name for result file naming")
+	legacyCvFlag := flags.Bool("legacy-cv", false, "Use the old mean/stddev CV convergence criterion instead of bootstrap confidence intervals")
+	cleanFlag := flags.BoolP("clean", "c", false, "Remove all cached files and exit")
+
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	config.WorkDir = CreateDirectory(*workDirFlag)
+	config.ResultDir = CreateDirectory(*resultDirFlag)
+	config.Timeout = *timeoutFlag
+	config.SessionID = *sessionIdFlag
+	config.LegacyCV = *legacyCvFlag
+
+	if *cleanFlag {
+		for _, name := range names {
+			config.RemoveDatabase(name)
+			fmt.Fprintf(os.Stderr, "The databse is deleted: %s\n", config.DatabasePath(name))
+		}
+		os.Exit(0)
+	}
+
+	return config
+}
+
+// システム情報の表示
+func PrintSystemInfo(title, dbType string, config *Config) {
+	fmt.Printf("=== %s ===\n", title)
+	fmt.Printf("Database type: %s\n", dbType)
+	fmt.Printf("Working directory: %s\n", config.WorkDir)
+	fmt.Printf("Result directory: %s\n", config.ResultDir)
+	fmt.Printf("Session ID: %s\n", config.SessionID)
+	fmt.Printf("Max data size: %d\n", config.DataSize)
+	fmt.Printf("Max trials: %d\n", MaxTrials)
+	fmt.Printf("Min trials: %d\n", MinTrials)
+	fmt.Printf("Timeout: %v\n", config.Timeout)
+	fmt.Printf("StdDev threshold: %.1f%%\n", CVThreshold*100)
+	fmt.Printf("Data type: 8-byte integers\n")
+	fmt.Printf("Append test divisions: %d\n", AppendDivision)
+	fmt.Printf("Query test divisions: %d\n", QueryDivision)
+	fmt.Println()
+}
+
+func FilterCvSufficient(gauge []uint64, s *Stats) []uint64 {
+	var result []uint64
+	for _, i := range gauge {
+		if !s.IsCVSufficient(i, CVThreshold) {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+// FilterConvergent は、まだ収束判定を満たしていないキーだけを返す。config.LegacyCV が
+// true の場合は既存の結果ファイルとの互換性のために従来の CV しきい値判定 (FilterCvSufficient
+// と同じ基準) を使い、それ以外はブートストラップ信頼区間の相対幅に基づく判定を使う。
+// レイテンシはGCの一時停止やページキャッシュミスで裾が重くなりやすく、CV は MaxTrials
+// 以内に収束しないことがあるため、こちらを既定とする。
+func FilterConvergent(gauge []uint64, s *Stats, config *Config) []uint64 {
+	var result []uint64
+	for _, i := range gauge {
+		var sufficient bool
+		if config.LegacyCV {
+			sufficient = s.IsCVSufficient(i, CVThreshold)
+		} else {
+			sufficient = s.IsCISufficient(i, CIThreshold)
+		}
+		if !sufficient {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+// 統計情報
+type Stats struct {
+	trials map[uint64][]float64
+}
+
+func NewStats() *Stats {
+	return &Stats{
+		trials: make(map[uint64][]float64),
+	}
+}
+
+func (s *Stats) Add(key uint64, value float64) {
+	trials, ok := s.trials[key]
+	if !ok {
+		trials = []float64{value}
+	} else {
+		trials = append(trials, value)
+	}
+	s.trials[key] = trials
+}
+
+func (s *Stats) Calculate(key uint64) (float64, float64, int) {
+	trials, ok := s.trials[key]
+	if !ok || len(trials) == 0 {
+		return 0, 0, len(trials)
+	}
+	sum := 0.0
+	for _, v := range trials {
+		sum += v
+	}
+	mean := sum / float64(len(trials))
+	sumSquaredDiff := 0.0
+	for _, v := range trials {
+		diff := v - mean
+		sumSquaredDiff += diff * diff
+	}
+	stddev := 0.0
+	if len(trials)-1 >= 1 {
+		variance := sumSquaredDiff / float64(len(trials)-1)
+		stddev = math.Sqrt(variance)
+	}
+	return mean, stddev, len(trials)
+}
+
+func (s *Stats) IsCVSufficient(x uint64, cv float64) bool {
+	mean, stddev, count := s.Calculate(x)
+	if count <= 2 {
+		return false
+	}
+	return stddev/mean < cv
+}
+
+func (s *Stats) MaxRelative() float64 {
+	relative := math.NaN()
+	for x, _ := range s.trials {
+		mean, stddev, _ := s.Calculate(x)
+		r := stddev / mean
+		if math.IsNaN(relative) || r > relative {
+			relative = r
+		}
+	}
+	return relative
+}
+
+// Percentile は key の試行値のうち、p (0.0〜1.0) 分位点の値を線形補間で求める。
+func (s *Stats) Percentile(key uint64, p float64) float64 {
+	trials, ok := s.trials[key]
+	if !ok || len(trials) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), trials...)
+	sort.Float64s(sorted)
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+	idx := p * float64(len(sorted)-1)
+	lo := int(math.Floor(idx))
+	hi := int(math.Ceil(idx))
+	if lo == hi {
+		return sorted[lo]
+	}
+	frac := idx - float64(lo)
+	return sorted[lo]*(1-frac) + sorted[hi]*frac
+}
+
+// BootstrapCI は key の試行値に対してブートストラップ法 (BootstrapResample 回の
+// 復元抽出による再標本化) で平均の 95% 信頼区間を求め、その 2.5/97.5 パーセンタイルを
+// 返す。試行回数が2未満の場合は (0, 0) を返す。
+func (s *Stats) BootstrapCI(key uint64) (float64, float64) {
+	trials, ok := s.trials[key]
+	if !ok || len(trials) < 2 {
+		return 0, 0
+	}
+	means := make([]float64, BootstrapResample)
+	for b := 0; b < BootstrapResample; b++ {
+		sum := 0.0
+		for j := 0; j < len(trials); j++ {
+			sum += trials[rand.Intn(len(trials))]
+		}
+		means[b] = sum / float64(len(trials))
+	}
+	sort.Float64s(means)
+	lo := means[int(0.025*float64(len(means)-1))]
+	hi := means[int(0.975*float64(len(means)-1))]
+	return lo, hi
+}
+
+// IsCISufficient は、key のブートストラップ信頼区間の相対幅 (ci_hi-ci_lo)/mean が
+// threshold 未満であれば収束したとみなす。IsCVSufficient と異なり、裾の重い分布でも
+// 統計的に妥当な根拠を持って打ち切ることができる。
+func (s *Stats) IsCISufficient(key uint64, threshold float64) bool {
+	mean, _, count := s.Calculate(key)
+	if count <= 2 {
+		return false
+	}
+	lo, hi := s.BootstrapCI(key)
+	return (hi-lo)/mean < threshold
+}
+
+// SaveSummary は、キーごとに試行回数・p50/p90/p99・平均・標準偏差・ブートストラップ
+// 信頼区間 (ci_lo, ci_hi) を1行にまとめた CSV を書き出す。生の試行値を列挙する Save と
+// 異なり、レイテンシのように裾の重い分布を要約して比較するために使う。
+func (s *Stats) SaveSummary(path, column1 string) {
+	file, err := os.Create(path)
+	if err != nil {
+		panic(fmt.Errorf("failed to save statistics: %w", err))
+	}
+	defer file.Close()
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	header := []string{column1, "N", "P50", "P90", "P99", "MEAN", "STDDEV", "CI_LO", "CI_HI"}
+	if err := writer.Write(header); err != nil {
+		panic(fmt.Errorf("failed to save header: %w", err))
+	}
+
+	keys := make([]uint64, 0, len(s.trials))
+	for key := range s.trials {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i] < keys[j]
+	})
+	for _, key := range keys {
+		mean, stddev, n := s.Calculate(key)
+		ciLo, ciHi := s.BootstrapCI(key)
+		record := []string{
+			strconv.FormatUint(key, 10),
+			strconv.Itoa(n),
+			strconv.FormatFloat(s.Percentile(key, 0.50), 'f', -1, 64),
+			strconv.FormatFloat(s.Percentile(key, 0.90), 'f', -1, 64),
+			strconv.FormatFloat(s.Percentile(key, 0.99), 'f', -1, 64),
+			strconv.FormatFloat(mean, 'f', -1, 64),
+			strconv.FormatFloat(stddev, 'f', -1, 64),
+			strconv.FormatFloat(ciLo, 'f', -1, 64),
+			strconv.FormatFloat(ciHi, 'f', -1, 64),
+		}
+		if err := writer.Write(record); err != nil {
+			panic(fmt.Errorf("failed to save data: %w", err))
+		}
+	}
+}
+
+func (s *Stats) Save(path, column1, column2 string) {
+	file, err := os.Create(path)
+	if err != nil {
+		panic(fmt.Errorf("failed to save statistics: %w", err))
+	}
+	defer file.Close()
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	if err := writer.Write([]string{column1, column2}); err != nil {
+		panic(fmt.Errorf("failed to save header: %w", err))
+	}
+
+	keys := make([]uint64, 0, len(s.trials))
+	for key := range s.trials {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i] < keys[j]
+	})
+	for _, key := range keys {
+		values := s.trials[key]
+		record := make([]string, len(values)+1)
+		record[0] = strconv.FormatUint(key, 10)
+		for i, value := range values {
+			record[i+1] = strconv.FormatFloat(value, 'f', -1, 64)
+		}
+		if err := writer.Write(record); err != nil {
+			panic(fmt.Errorf("failed to save data: %w", err))
+		}
+	}
+}
+
+func (c *Config) DatabasePath(name string) string {
+	return filepath.Join(c.WorkDir, fmt.Sprintf("slate_benchmark-%s.db", name))
+}
+
+func (c *Config) RemoveDatabase(name string) {
+	path := c.DatabasePath(name)
+	os.RemoveAll(path)
+}
+
+func (c *Config) ResultFile(id string) string {
+	return filepath.Join(c.ResultDir, fmt.Sprintf("%s-%s.csv", c.SessionID, id))
+}
+
+func CreateDirectory(path string) string {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		panic(fmt.Errorf("Error: Failed to get absolute path for '%s': %v\n", path, err))
+	}
+	if err := os.MkdirAll(absPath, 0755); err != nil {
+		panic(fmt.Errorf("Error: Failed to create working directory '%s': %v\n", absPath, err))
+	}
+	return absPath
+}
+
+func FileOrDirectorySize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cannot access to path: '%s': %v\n", path, err)
+		return 0
+	}
+
+	if !info.IsDir() {
+		return info.Size()
+	}
+
+	var totalSize int64
+	filepath.WalkDir(path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot access to path: '%s': %v\n", path, err)
+			return nil
+		} else if !d.IsDir() {
+			info, err := d.Info()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "cannot access to path: '%s': %v\n", path, err)
+			} else {
+				totalSize += info.Size()
+			}
+		}
+		return nil
+	})
+	return totalSize
+}
+
+func Splitmix64(x uint64) uint64 {
+	z := x
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+func IntToKey(value uint64) []byte {
+	key := make([]byte, 8)
+	binary.LittleEndian.PutUint64(key, value)
+	return key
+}
+
+func IntToValue(value uint64) []byte {
+	data := make([]byte, 8)
+	binary.LittleEndian.PutUint64(data, Splitmix64(value))
+	return data
+}
+
+func ValueToInt(bytes []byte) uint64 {
+	if len(bytes) != 8 {
+		panic(fmt.Errorf("invalid value byte size: %d", len(bytes)))
+	}
+	return binary.LittleEndian.Uint64(bytes)
+}
+
+func Linspace(min, max uint64, n int) []uint64 {
+	if n <= 1 {
+		panic("n must be greater than 1")
+	}
+	result := make([]uint64, n)
+	step := float64(max-min) / float64(n-1)
+	for i := 0; i < n; i++ {
+		val := float64(min) + step*float64(i)
+		result[i] = uint64(math.Round(val))
+	}
+	return result
+}
+
+// logspace は、minからmaxまでをn個の対数的に分割されたu64に分割する
+func Logspace(min, max uint64, n int) []uint64 {
+	if min == 0 {
+		panic("min must be positive for logspace")
+	}
+	if n <= 1 {
+		panic("n must be greater than 1")
+	}
+	result := make([]uint64, n)
+	logMin := math.Log(float64(min))
+	logMax := math.Log(float64(max))
+	step := (logMax - logMin) / float64(n-1)
+	for i := 0; i < n; i++ {
+		val := math.Exp(logMin + step*float64(i))
+		result[i] = uint64(math.Round(val))
+	}
+	return result
+}
@@ -0,0 +1,230 @@
+package doltdb
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+
+	_ "github.com/dolthub/driver"
+
+	"slate_benchmark/common"
+)
+
+type DoltDBCUT struct {
+	Path string
+	Db   *sql.DB
+}
+
+var _ common.CUT = (*DoltDBCUT)(nil)
+
+func NewDoltDBCUT(path string) DoltDBCUT {
+	return DoltDBCUT{Path: path}
+}
+
+func (c *DoltDBCUT) Open() {
+	if c.Db != nil {
+		return
+	}
+	common.CreateDirectory(c.Path)
+	dsn := fmt.Sprintf("file://%s?commitname=%s&commitemail=%s&database=%s",
+		c.Path,
+		url.QueryEscape("TAKAMI Torao"),
+		"koiroha@gmail.com",
+		"slate",
+	)
+	db, err := sql.Open("dolt", dsn)
+	if err != nil {
+		panic(fmt.Errorf("failed to create doltdb: %v", err))
+	}
+	c.Db = db
+}
+
+func (c *DoltDBCUT) Close() {
+	if c.Db != nil {
+		c.Db.Close()
+		c.Db = nil
+	}
+	if _, err := os.Stat(c.Path); err == nil {
+		if err = os.RemoveAll(c.Path); err != nil {
+			panic(fmt.Errorf("failed to remove file or directory: %v; %s", err, c.Path))
+		}
+	}
+}
+
+// 指定されたディレクトリに DoltDB を作成します。
+func (c *DoltDBCUT) MeasureAppend(n uint64) (time.Duration, uint64) {
+	c.Db.Exec(`CREATE DATABASE slate`)
+	_, err := c.Db.Exec(`CREATE TABLE IF NOT EXISTS sequence_data(i BIGINT PRIMARY KEY, value BIGINT)`)
+	if err != nil {
+		panic(fmt.Errorf("failed to create table: %v", err))
+	}
+	var count int64
+	err = c.Db.QueryRow(`SELECT COUNT(*) FROM sequence_data`).Scan(&count)
+	if err != nil {
+		panic(err)
+	}
+
+	runtime.GC()
+	start := time.Now()
+	for i := uint64(count) + 1; i <= n; i++ {
+		value := int64(common.Splitmix64(i))
+		_, err = c.Db.Exec(`
+		INSERT INTO sequence_data(i, value) VALUES(?, ?);
+		CALL DOLT_COMMIT('-a', '-m', 'commit');
+		`, i, value)
+		if err != nil {
+			panic(fmt.Errorf("failed to prepare statement: %v", err))
+		}
+	}
+	duration := time.Since(start)
+
+	return duration, uint64(common.FileOrDirectorySize(c.Path))
+}
+
+// MeasureAppendBatched は MeasureAppend と同様に n 件まで INSERT を行うが、DOLT_COMMIT を
+// batchSize 件の INSERT ごとにしか呼び出さない。これによりコミット粒度を変えた比較ができる。
+func (c *DoltDBCUT) MeasureAppendBatched(n, batchSize uint64) (time.Duration, uint64) {
+	c.Db.Exec(`CREATE DATABASE slate`)
+	_, err := c.Db.Exec(`CREATE TABLE IF NOT EXISTS sequence_data(i BIGINT PRIMARY KEY, value BIGINT)`)
+	if err != nil {
+		panic(fmt.Errorf("failed to create table: %v", err))
+	}
+	var count int64
+	err = c.Db.QueryRow(`SELECT COUNT(*) FROM sequence_data`).Scan(&count)
+	if err != nil {
+		panic(err)
+	}
+
+	runtime.GC()
+	start := time.Now()
+	for i := uint64(count) + 1; i <= n; i++ {
+		value := int64(common.Splitmix64(i))
+		_, err = c.Db.Exec(`INSERT INTO sequence_data(i, value) VALUES(?, ?);`, i, value)
+		if err != nil {
+			panic(fmt.Errorf("failed to prepare statement: %v", err))
+		}
+		if i%batchSize == 0 || i == n {
+			_, err = c.Db.Exec(`CALL DOLT_COMMIT('-a', '-m', 'commit');`)
+			if err != nil {
+				panic(fmt.Errorf("failed to commit: %v", err))
+			}
+		}
+	}
+	duration := time.Since(start)
+
+	return duration, uint64(common.FileOrDirectorySize(c.Path))
+}
+
+// MeasureConcurrentGets は workers 個のゴルーチンから共有の *sql.DB を介して並行に
+// SELECT を発行する。database/sql のハンドルはそれ自体が複数ゴルーチンからの利用を
+// 想定したコネクションプールであるため、IAVL のような別個のスナップショットは不要。
+func (c *DoltDBCUT) MeasureConcurrentGets(is []uint64, workers int) (map[uint64]time.Duration, float64) {
+	jobs := make(chan uint64, len(is))
+	for _, i := range is {
+		jobs <- i
+	}
+	close(jobs)
+
+	var mu sync.Mutex
+	result := make(map[uint64]time.Duration, len(is))
+	var wg sync.WaitGroup
+	start := time.Now()
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				var value int64
+				qstart := time.Now()
+				err := c.Db.QueryRow(`SELECT value FROM sequence_data WHERE i=?`, i).Scan(&value)
+				if err != nil {
+					panic(err)
+				}
+				duration := time.Since(qstart)
+				if value != int64(common.Splitmix64(i)) {
+					panic(fmt.Errorf("The value read for i=%d is incorrect: %d != %d", i, common.Splitmix64(i), value))
+				}
+				mu.Lock()
+				result[i] = duration
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	return result, float64(len(is)) / elapsed.Seconds()
+}
+
+// MeasureProof は IAVL の ICS-23 証明に相当するものとして、DoltDB のコミットグラフと
+// データハッシュ API を使う。dolt_diff_sequence_data でキー i を追加したコミットを特定し
+// (経路つきの Merkle 証明生成に相当)、そのコミットが dolt_log('HEAD') で HEAD から
+// 到達可能であることを確認する (検証に相当)。ProofBytes はコミットハッシュの文字列長で、
+// IAVL 側のシリアライズされた証明サイズに対応する。
+func (c *DoltDBCUT) MeasureProof(is []uint64) map[uint64]common.ProofResult {
+	result := make(map[uint64]common.ProofResult)
+
+	var head string
+	if err := c.Db.QueryRow(`SELECT DOLT_HASHOF('HEAD')`).Scan(&head); err != nil {
+		for _, i := range is {
+			result[i] = common.ProofResult{Err: err}
+		}
+		return result
+	}
+
+	for _, i := range is {
+		runtime.GC()
+
+		genStart := time.Now()
+		var commitHash string
+		err := c.Db.QueryRow(`
+			SELECT to_commit FROM dolt_diff_sequence_data
+			WHERE to_i = ? AND diff_type = 'added'
+			ORDER BY to_commit_date DESC LIMIT 1`, i).Scan(&commitHash)
+		genNanos := time.Since(genStart)
+		if err != nil {
+			result[i] = common.ProofResult{Err: err}
+			continue
+		}
+
+		verifyStart := time.Now()
+		var n int
+		err = c.Db.QueryRow(`SELECT COUNT(*) FROM dolt_log('HEAD') WHERE commit_hash = ?`, commitHash).Scan(&n)
+		verifyNanos := time.Since(verifyStart)
+		if err != nil {
+			result[i] = common.ProofResult{Err: err}
+			continue
+		}
+		if n == 0 {
+			result[i] = common.ProofResult{Err: fmt.Errorf("commit %s for i=%d is not an ancestor of HEAD (%s)", commitHash, i, head)}
+			continue
+		}
+
+		result[i] = common.ProofResult{GenNanos: genNanos, VerifyNanos: verifyNanos, ProofBytes: len(commitHash)}
+	}
+	return result
+}
+
+// 既存のルートハッシュから DoltDB をロードし、値を取得
+func (c *DoltDBCUT) MeasureGets(is []uint64) map[uint64]time.Duration {
+	result := make(map[uint64]time.Duration)
+	for _, i := range is {
+		runtime.GC()
+		var value int64
+		start := time.Now()
+		err := c.Db.QueryRow(`SELECT value FROM sequence_data WHERE i=?`, i).Scan(&value)
+		if err != nil {
+			panic(err)
+		}
+		duration := time.Since(start)
+		result[i] = duration
+		if value != int64(common.Splitmix64(i)) {
+			panic(fmt.Errorf("The value read for i=%d is incorrect: %d != %d", i, common.Splitmix64(i), value))
+		}
+	}
+	return result
+}
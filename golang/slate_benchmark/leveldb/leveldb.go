@@ -0,0 +1,165 @@
+package leveldb
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+
+	"slate_benchmark/common"
+)
+
+// LevelDBCUT は生の goleveldb に対して直接 Put/Get を行う、IAVL/DoltDB と比較するための
+// 下限値リファレンス実装です。IAVL のノードは最終的にこの goleveldb 上に保存されるため、
+// Merkle 木レイヤーが追加しているコストと、下層の LSM 自体のコストを切り分けられます。
+type LevelDBCUT struct {
+	Path  string
+	DB    *leveldb.DB
+	Sync  bool
+	count uint64
+}
+
+var _ common.CUT = (*LevelDBCUT)(nil)
+
+// NewLevelDBCUT は、sync=true の場合に各書き込みで WriteOptions{Sync: true} を指定する
+// LevelDBCUT を作成します。これにより IAVL が SaveVersion を通じて暗黙に支払っている
+// fsync のコストを単独で計測できます。
+func NewLevelDBCUT(path string, sync bool) LevelDBCUT {
+	return LevelDBCUT{Path: path, Sync: sync}
+}
+
+func (c *LevelDBCUT) Open() {
+	if c.DB != nil {
+		return
+	}
+	db, err := leveldb.OpenFile(c.Path, nil)
+	if err != nil {
+		panic(fmt.Errorf("failed to create leveldb: %v", err))
+	}
+	c.DB = db
+	c.count = 0
+}
+
+func (c *LevelDBCUT) Close() {
+	if c.DB != nil {
+		c.DB.Close()
+		c.DB = nil
+	}
+	if _, err := os.Stat(c.Path); err == nil {
+		if err = os.RemoveAll(c.Path); err != nil {
+			panic(fmt.Errorf("failed to remove file or directory: %v; %s", err, c.Path))
+		}
+	}
+}
+
+// 指定されたディレクトリに goleveldb を作成し、n 件まで Put します。
+func (c *LevelDBCUT) MeasureAppend(n uint64) (time.Duration, uint64) {
+	wo := &opt.WriteOptions{Sync: c.Sync}
+	runtime.GC()
+	start := time.Now()
+	for c.count < n {
+		c.count++
+		i := c.count
+		if err := c.DB.Put(common.IntToKey(i), common.IntToValue(i), wo); err != nil {
+			panic(fmt.Errorf("failed to update leveldb: %v", err))
+		}
+	}
+	duration := time.Since(start)
+	return duration, uint64(common.FileOrDirectorySize(c.Path))
+}
+
+// MeasureAppendBatched は、batchSize 件の Put ごとに leveldb.Batch をまとめて書き込む。
+func (c *LevelDBCUT) MeasureAppendBatched(n, batchSize uint64) (time.Duration, uint64) {
+	wo := &opt.WriteOptions{Sync: c.Sync}
+	runtime.GC()
+	start := time.Now()
+	batch := new(leveldb.Batch)
+	for c.count < n {
+		c.count++
+		i := c.count
+		batch.Put(common.IntToKey(i), common.IntToValue(i))
+		if i%batchSize == 0 || i == n {
+			if err := c.DB.Write(batch, wo); err != nil {
+				panic(fmt.Errorf("failed to update leveldb: %v", err))
+			}
+			batch.Reset()
+		}
+	}
+	duration := time.Since(start)
+	return duration, uint64(common.FileOrDirectorySize(c.Path))
+}
+
+// MeasureConcurrentGets は workers 個のゴルーチンから共有の *leveldb.DB に対して並行に
+// Get を発行する。goleveldb の DB は複数ゴルーチンからの同時利用が安全なため、IAVL の
+// ような別個のスナップショットは不要。
+func (c *LevelDBCUT) MeasureConcurrentGets(is []uint64, workers int) (map[uint64]time.Duration, float64) {
+	jobs := make(chan uint64, len(is))
+	for _, i := range is {
+		jobs <- i
+	}
+	close(jobs)
+
+	var mu sync.Mutex
+	result := make(map[uint64]time.Duration, len(is))
+	var wg sync.WaitGroup
+	start := time.Now()
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				qstart := time.Now()
+				value, err := c.DB.Get(common.IntToKey(i), nil)
+				if err != nil {
+					panic(err)
+				}
+				duration := time.Since(qstart)
+				v := common.ValueToInt(value)
+				if v != common.Splitmix64(i) {
+					panic(fmt.Errorf("The value read for i=%d is incorrect: %d != %d", i, common.Splitmix64(i), v))
+				}
+				mu.Lock()
+				result[i] = duration
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	return result, float64(len(is)) / elapsed.Seconds()
+}
+
+// MeasureProof は常に common.ErrProofUnsupported を返す。生の goleveldb は鍵に対する
+// 証明を持たないため、BenchmarkProof 側にこの CUT をスキップさせる。
+func (c *LevelDBCUT) MeasureProof(is []uint64) map[uint64]common.ProofResult {
+	result := make(map[uint64]common.ProofResult)
+	for _, i := range is {
+		result[i] = common.ProofResult{Err: common.ErrProofUnsupported}
+	}
+	return result
+}
+
+// 既存の goleveldb から値を取得
+func (c *LevelDBCUT) MeasureGets(is []uint64) map[uint64]time.Duration {
+	result := make(map[uint64]time.Duration)
+	for _, i := range is {
+		runtime.GC()
+		start := time.Now()
+		value, err := c.DB.Get(common.IntToKey(i), nil)
+		if err != nil {
+			panic(err)
+		}
+		duration := time.Since(start)
+		result[i] = duration
+		v := common.ValueToInt(value)
+		if v != common.Splitmix64(i) {
+			panic(fmt.Errorf("The value read for i=%d is incorrect: %d != %d", i, common.Splitmix64(i), v))
+		}
+	}
+	return result
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"slate_benchmark/common"
+	"slate_benchmark/iavl"
+	"slate_benchmark/doltdb"
+	"slate_benchmark/leveldb"
+)
+
+func main() {
+	config := common.ParseCommandLine([]string{
+		"query-iavl-leveldb",
+		"append-iavl-leveldb",
+		"append-batch-iavl-leveldb",
+		"query-doltdb",
+		"append-doltdb",
+		"append-batch-doltdb-file",
+		"leveldb-sync",
+		"leveldb-nosync",
+		"concurrent-get-iavl-leveldb",
+		"concurrent-get-doltdb-file",
+		"concurrent-get-leveldb-sync",
+		"concurrent-get-leveldb-nosync",
+	}, "Performance Benchmark Tool", `Performance Benchmark Tool
+
+  This tool performs comprehensive performance benchmarking of IAVL+ (Immutable
+  AVL+) trees using LevelDB and DoltDB as the persistent storage backend. It
+	measures both time and space complexity for append operations and query
+	performance across different data sizes.
+`)
+	common.PrintSystemInfo("Benchmark", "File", config)
+
+	iavl := iavl.NewIAVLCUT(config.DatabasePath("iavl-leveldb"))
+	common.BenchmarkAppend(config, "append-iavl-leveldb", "volume-iavl-leveldb", &iavl)
+	common.BenchmarkGet(config, "get-iavl-leveldb", &iavl)
+	common.BenchmarkAppendBatch(config, "append-batch-iavl-leveldb", &iavl, common.DefaultBatchSizes)
+	common.BenchmarkProof(config, "proof-gen-iavl-leveldb", "proof-verify-iavl-leveldb", "proof-size-iavl-leveldb", &iavl)
+	common.BenchmarkConcurrentGet(config, "concurrent-get-iavl-leveldb", &iavl)
+	iavl.Close()
+
+	doltdb := doltdb.NewDoltDBCUT(config.DatabasePath("doltdb-file"))
+	common.BenchmarkAppend(config, "append-doltdb-file", "volume-doltdb-file", &doltdb)
+	common.BenchmarkGet(config, "get-doltdb-file", &doltdb)
+	common.BenchmarkAppendBatch(config, "append-batch-doltdb-file", &doltdb, common.DefaultBatchSizes)
+	common.BenchmarkProof(config, "proof-gen-doltdb-file", "proof-verify-doltdb-file", "proof-size-doltdb-file", &doltdb)
+	common.BenchmarkConcurrentGet(config, "concurrent-get-doltdb-file", &doltdb)
+	doltdb.Close()
+
+	leveldbSync := leveldb.NewLevelDBCUT(config.DatabasePath("leveldb-sync"), true)
+	common.BenchmarkAppend(config, "append-leveldb-sync", "volume-leveldb-sync", &leveldbSync)
+	common.BenchmarkGet(config, "get-leveldb-sync", &leveldbSync)
+	common.BenchmarkAppendBatch(config, "append-batch-leveldb-sync", &leveldbSync, common.DefaultBatchSizes)
+	common.BenchmarkProof(config, "proof-gen-leveldb-sync", "proof-verify-leveldb-sync", "proof-size-leveldb-sync", &leveldbSync)
+	common.BenchmarkConcurrentGet(config, "concurrent-get-leveldb-sync", &leveldbSync)
+	leveldbSync.Close()
+
+	leveldbNoSync := leveldb.NewLevelDBCUT(config.DatabasePath("leveldb-nosync"), false)
+	common.BenchmarkAppend(config, "append-leveldb-nosync", "volume-leveldb-nosync", &leveldbNoSync)
+	common.BenchmarkGet(config, "get-leveldb-nosync", &leveldbNoSync)
+	common.BenchmarkAppendBatch(config, "append-batch-leveldb-nosync", &leveldbNoSync, common.DefaultBatchSizes)
+	common.BenchmarkProof(config, "proof-gen-leveldb-nosync", "proof-verify-leveldb-nosync", "proof-size-leveldb-nosync", &leveldbNoSync)
+	common.BenchmarkConcurrentGet(config, "concurrent-get-leveldb-nosync", &leveldbNoSync)
+	leveldbNoSync.Close()
+}
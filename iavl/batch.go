@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cosmos/iavl"
+	"github.com/cosmos/iavl/db"
+)
+
+// DefaultBatchSizes は append バッチサイズ掃引のデフォルト値。SaveVersion のたびに生じる
+// WAL/コンパクションのオーバーヘッドが、コミット粒度によってどれだけ償却されるかを示す。
+//
+// 同等の DOLT_COMMIT バッチグルーピングはこのモジュールでは提供しない。この iavl/ は
+// cosmos/iavl + goleveldb/pebble 専用の単一バックエンドツールであり、比較対象に DoltDB
+// ドライバへの依存を持ち込む理由がない。DoltDB 側のバッチコミット計測は common.CUT ベースの
+// harness (golang/doltdb/doltdb.go の MeasureAppendBatched) で提供している。
+var DefaultBatchSizes = []uint64{1, 10, 100, 1000}
+
+// parseBatchSizes はカンマ区切りのバッチサイズ文字列を解析する。
+func parseBatchSizes(s string) []uint64 {
+	var sizes []uint64
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			panic(fmt.Errorf("invalid batch size %q: %v", part, err))
+		}
+		sizes = append(sizes, n)
+	}
+	return sizes
+}
+
+// measureAppendBatched は、SaveVersion を batchSize 件の Set ごとに呼び出すことで
+// バージョンコミットの頻度を落とした Append を計測する。batchSize=1 は measureAppend と等価。
+func measureAppendBatched(path string, n, batchSize uint64, stats *Stats) (int64, compactionIOStats, []int) {
+	leveldb, err := db.NewGoLevelDB("slate", path)
+	if err != nil {
+		panic(fmt.Errorf("failed to create leveldb: %v", err))
+	}
+	defer leveldb.Close()
+	tree := iavl.NewMutableTree(leveldb, 0, false, iavl.NewNopLogger())
+	defer tree.Close()
+
+	before := readCompactionIOStats(leveldb.RawDB())
+	start := time.Now()
+	for i := uint64(1); i <= n; i++ {
+		_, err := tree.Set(intToKey(i), intToValue(i))
+		if err != nil {
+			panic(fmt.Errorf("failed to update iavl database: %v", err))
+		}
+		if i%batchSize == 0 || i == n {
+			tree.Hash()
+			_, _, err = tree.SaveVersion()
+			if err != nil {
+				panic(fmt.Errorf("failed to version iavl database: %v", err))
+			}
+		}
+	}
+	duration := time.Since(start)
+	stats.Add(n, float64(duration.Nanoseconds())/1000.0/1000.0)
+	after := readCompactionIOStats(leveldb.RawDB())
+	levels := readLevelTableCounts(leveldb.RawDB())
+
+	return fileOrDirectorySize(path), after.sub(before), levels
+}
+
+// Append 性能のバッチサイズ掃引ベンチマーク。1回の SaveVersion に束ねる Set 件数を変えながら
+// append-iavl-leveldb と同じ測定を繰り返し、バッチサイズごとに別々の CSV 系列を出力する。
+func benchmarkAppendBatch(config *Config, idPrefix string) {
+	fmt.Println("\n=== Append Batch-Size Sweep Benchmark ===")
+
+	for _, batchSize := range config.BatchSizes {
+		id := fmt.Sprintf("%s-batch%d", idPrefix, batchSize)
+		fmt.Printf("--- batch size: %d ---\n", batchSize)
+		fmt.Println("DataSize\tMean(ms)\tStdDev(ms)\tCV(%)\t\tTrials")
+		fmt.Println("--------\t--------\t----------\t-----\t\t------")
+
+		ns := linspace(1, MaxDataSize, AppendDivision)
+		timeComplexity := NewStats()
+		spaceComplexity := NewStats()
+		for _, n := range ns {
+			runUntilConverged(func(i int) float64 {
+				config.RemoveDatabase("iavl")
+				space, _, _ := measureAppendBatched(config.DatabasePath("iavl"), n, batchSize, timeComplexity)
+				spaceComplexity.AddLarger(n, float64(space))
+				return timeComplexity.Percentile(n, 0.99)
+			})
+			mean, stddev, size := timeComplexity.Calculate(n)
+			fmt.Printf("%d\t\t%.2fms\t\t%.2fms\t\t%.2f\t\t%d\n",
+				n, mean, stddev, 2*stddev/mean, size)
+		}
+		config.RemoveDatabase("iavl")
+
+		timeComplexity.SaveSummary(config.ResultFile(fmt.Sprintf("%s_time", id)), "SIZE")
+		if config.HDR {
+			timeComplexity.Save(config.ResultFile(fmt.Sprintf("%s_time_raw", id)), "SIZE", "TIME")
+		}
+		spaceComplexity.Save(config.ResultFile(fmt.Sprintf("%s_space", id)), "SIZE", "BYTES")
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/cosmos/iavl"
+	"github.com/cosmos/iavl/db"
+)
+
+// DefaultReaderCounts は並行リーダーベンチマークで掃引するゴルーチン並列度のデフォルト値。
+var DefaultReaderCounts = []uint64{1, 2, 4, 8, 16}
+
+// measureConcurrentQuery は readers 個のゴルーチンから並行に各キーをその挿入時点の
+// バージョンで読み取り、達成したスループット (ops/sec) を返す。各リクエストのレイテンシは
+// readers をキーとして stats に記録され、並列度ごとの p50/p99 を後で取り出せるようにする。
+// MutableTree 自体は並行な読み取りに対して安全ではないため (cosmos/iavl の
+// MutableTree の doc comment を参照)、ゴルーチンを起動する前に必要なバージョンの
+// ImmutableTree スナップショットを一括で取得しておき、各ワーカーはその Get だけを呼ぶ。
+func measureConcurrentQuery(path string, is []uint64, readers uint64, stats *Stats) float64 {
+	leveldb, err := db.NewGoLevelDB("slate", path)
+	if err != nil {
+		panic(fmt.Errorf("failed to create leveldb: %v", err))
+	}
+	defer leveldb.Close()
+	tree := iavl.NewMutableTree(leveldb, 0, false, iavl.NewNopLogger())
+	defer tree.Close()
+	if _, err := tree.Load(); err != nil {
+		panic(fmt.Errorf("failed to load tree: %v", err))
+	}
+
+	snapshots := make(map[int64]*iavl.ImmutableTree)
+	for _, i := range is {
+		version := int64(i)
+		if _, ok := snapshots[version]; ok {
+			continue
+		}
+		snapshot, err := tree.GetImmutable(version)
+		if err != nil {
+			panic(fmt.Errorf("failed to snapshot iavl tree at version %d: %v", version, err))
+		}
+		snapshots[version] = snapshot
+	}
+
+	latencies := make(chan float64, len(is))
+	var wg sync.WaitGroup
+	start := time.Now()
+	for r := uint64(0); r < readers; r++ {
+		wg.Add(1)
+		go func(worker uint64) {
+			defer wg.Done()
+			for idx := worker; idx < uint64(len(is)); idx += readers {
+				i := is[idx]
+				qstart := time.Now()
+				value, err := snapshots[int64(i)].Get(intToKey(i))
+				if err != nil {
+					panic(err)
+				}
+				latencies <- float64(time.Since(qstart).Nanoseconds()) / 1000.0 / 1000.0
+				if valueToInt(value) != splitmix64(i) {
+					panic(fmt.Errorf("The value read for i=%d is incorrect: %d != %d", i, splitmix64(i), valueToInt(value)))
+				}
+			}
+		}(r)
+	}
+	wg.Wait()
+	close(latencies)
+	duration := time.Since(start)
+
+	total := 0
+	for ms := range latencies {
+		stats.Add(readers, ms)
+		total++
+	}
+	return float64(total) / duration.Seconds()
+}
+
+// 並行リーダーによる Query 性能のベンチマーク。リーダー数ごとにスループットと
+// レイテンシ分布を別々の CSV 系列に出力する。
+func benchmarkConcurrentQuery(config *Config, idPrefix string) {
+	fmt.Println("\n=== Concurrent Query Benchmark ===")
+
+	fmt.Printf("Creating iavl with %d entries...\n", MaxDataSize)
+	config.RemoveDatabase("iavl")
+	measureAppend(config.DatabasePath("iavl"), MaxDataSize, NewStats())
+
+	is := logspace(1, MaxDataSize, QueryDivision)
+
+	for _, readers := range config.ReaderCounts {
+		id := fmt.Sprintf("%s-readers%d", idPrefix, readers)
+		fmt.Printf("--- readers: %d ---\n", readers)
+		fmt.Println("Trial\tThroughput(ops/s)\tP50(ms)\t\tP99(ms)")
+		fmt.Println("-----\t-----------------\t-------\t\t-------")
+
+		throughput := NewStats()
+		latency := NewStats()
+		runUntilConverged(func(i int) float64 {
+			rand.Shuffle(len(is), func(a, b int) {
+				is[a], is[b] = is[b], is[a]
+			})
+			ops := measureConcurrentQuery(config.DatabasePath("iavl"), is, readers, latency)
+			throughput.Add(readers, ops)
+			if (i+1)%100 == 0 {
+				fmt.Printf("  [%d/%d] readers=%d: p99=%.3fms throughput=%.1fops/s\n",
+					i+1, MaxTrials, readers, latency.Percentile(readers, 0.99), ops)
+			}
+			return latency.Percentile(readers, 0.99)
+		})
+		mean, _, _ := throughput.Calculate(readers)
+		fmt.Printf("%d\t\t%.1f\t\t\t%.3f\t\t%.3f\n",
+			readers, mean, latency.Percentile(readers, 0.50), latency.Percentile(readers, 0.99))
+
+		throughput.SaveSummary(config.ResultFile(fmt.Sprintf("%s_throughput", id)), "READERS")
+		latency.SaveSummary(config.ResultFile(fmt.Sprintf("%s_latency", id)), "READERS")
+		if config.HDR {
+			latency.Save(config.ResultFile(fmt.Sprintf("%s_latency_raw", id)), "READERS", "TIME")
+		}
+	}
+	config.RemoveDatabase("iavl")
+}
@@ -10,12 +10,14 @@ import (
 	"math/rand"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strconv"
 	"time"
 
 	"github.com/cosmos/iavl"
 	"github.com/cosmos/iavl/db"
+	"github.com/syndtr/goleveldb/leveldb"
 )
 
 // ベンチマーク設定
@@ -31,8 +33,65 @@ const (
 
 )
 
+// compactionIOStats は、LevelDB の "leveldb.iostats" プロパティから得られるバックグラウンド
+// コンパクションによる実効ディスク読み書き量を表す。
+type compactionIOStats struct {
+	ReadBytes  uint64
+	WriteBytes uint64
+}
+
+// sub は2つの累積 I/O 統計の差分を取り、その区間で発生した分だけを返す。
+func (s compactionIOStats) sub(before compactionIOStats) compactionIOStats {
+	return compactionIOStats{
+		ReadBytes:  s.ReadBytes - before.ReadBytes,
+		WriteBytes: s.WriteBytes - before.WriteBytes,
+	}
+}
+
+var iostatsPattern = regexp.MustCompile(`Read\(MB\):([0-9.]+)\s+Write\(MB\):([0-9.]+)`)
+
+// readCompactionIOStats は "leveldb.iostats" プロパティ ("Read(MB):X Write(MB):Y") を解析し、
+// バイト単位の累積読み書き量を返す。
+func readCompactionIOStats(ldb *leveldb.DB) compactionIOStats {
+	raw, err := ldb.GetProperty("leveldb.iostats")
+	if err != nil {
+		panic(fmt.Errorf("failed to read leveldb.iostats: %v", err))
+	}
+	m := iostatsPattern.FindStringSubmatch(raw)
+	if m == nil {
+		panic(fmt.Errorf("unexpected leveldb.iostats format: %q", raw))
+	}
+	readMB, _ := strconv.ParseFloat(m[1], 64)
+	writeMB, _ := strconv.ParseFloat(m[2], 64)
+	return compactionIOStats{
+		ReadBytes:  uint64(readMB * 1024 * 1024),
+		WriteBytes: uint64(writeMB * 1024 * 1024),
+	}
+}
+
+var levelStatsPattern = regexp.MustCompile(`(?m)^\s*(\d+)\s*\|\s*(\d+)\s*\|`)
+
+// readLevelTableCounts は "leveldb.stats" プロパティを解析し、レベルごとの SSTable 数を返す。
+// インデックスはレベル番号に対応する。
+func readLevelTableCounts(ldb *leveldb.DB) []int {
+	raw, err := ldb.GetProperty("leveldb.stats")
+	if err != nil {
+		panic(fmt.Errorf("failed to read leveldb.stats: %v", err))
+	}
+	var counts []int
+	for _, m := range levelStatsPattern.FindAllStringSubmatch(raw, -1) {
+		level, _ := strconv.Atoi(m[1])
+		tables, _ := strconv.Atoi(m[2])
+		for len(counts) <= level {
+			counts = append(counts, 0)
+		}
+		counts[level] = tables
+	}
+	return counts
+}
+
 // 指定されたディレクトリに IAVL を作成します。
-func measureAppend(path string, n uint64, stats *Stats) int64 {
+func measureAppend(path string, n uint64, stats *Stats) (int64, compactionIOStats, []int) {
 
 	// 永続化ストレージ上で新しい IAVL データベースを作成
 	leveldb, err := db.NewGoLevelDB("slate", path)
@@ -43,6 +102,7 @@ func measureAppend(path string, n uint64, stats *Stats) int64 {
 	db := iavl.NewMutableTree(leveldb, 0, false, iavl.NewNopLogger())
 	defer db.Close()
 
+	before := readCompactionIOStats(leveldb.RawDB())
 	start := time.Now()
 	for i := uint64(1); i <= n; i++ {
 		_, err := db.Set(intToKey(i), intToValue(i))
@@ -56,8 +116,10 @@ func measureAppend(path string, n uint64, stats *Stats) int64 {
 	}
 	duration := time.Since(start)
 	stats.Add(n, float64(duration.Nanoseconds())/1000.0/1000.0)
+	after := readCompactionIOStats(leveldb.RawDB())
+	levels := readLevelTableCounts(leveldb.RawDB())
 
-	return fileOrDirectorySize(path)
+	return fileOrDirectorySize(path), after.sub(before), levels
 }
 
 // 既存のルートハッシュから Trie をロードし、値を取得
@@ -96,27 +158,83 @@ func benchmarkAppend(config *Config, id string) {
 	ns := linspace(1, MaxDataSize, AppendDivision)
 	timeComplexity := NewStats()
 	spaceComplexity := NewStats()
+	compactionRead := NewStats()
+	compactionWrite := NewStats()
+	levelCounts := make(map[uint64][]int)
 	for _, n := range ns {
-		start := time.Now()
-		for i := 0; i < MaxTrials; i++ {
+		runUntilConverged(func(i int) float64 {
 			config.RemoveDatabase("iavl")
-			space := measureAppend(config.DatabasePath("iavl"), n, timeComplexity)
+			space, io, levels := measureAppend(config.DatabasePath("iavl"), n, timeComplexity)
 			spaceComplexity.AddLarger(n, float64(space))
-			if i+1 >= MinTrials {
-				mean, stddev, _ := timeComplexity.Calculate(n)
-				if 2*stddev/mean <= StdDevThreshold || time.Since(start) >= MaxDuration {
-					break
-				}
+			compactionRead.Add(n, float64(io.ReadBytes))
+			compactionWrite.Add(n, float64(io.WriteBytes))
+			if i == 0 {
+				levelCounts[n] = levels
 			}
-		}
+			return timeComplexity.Percentile(n, 0.99)
+		})
 		mean, stddev, size := timeComplexity.Calculate(n)
 		fmt.Printf("%d\t\t%.2fms\t\t%.2fms\t\t%.2f\t\t%d\n",
 			n, mean, stddev, 2*stddev/mean, size)
 	}
 	config.RemoveDatabase("iavl")
 
-	timeComplexity.Save(config.ResultFile(fmt.Sprintf("%s_time", id)), "SIZE", "TIME")
+	timeComplexity.SaveSummary(config.ResultFile(fmt.Sprintf("%s_time", id)), "SIZE")
+	if config.HDR {
+		timeComplexity.Save(config.ResultFile(fmt.Sprintf("%s_time_raw", id)), "SIZE", "TIME")
+	}
 	spaceComplexity.Save(config.ResultFile(fmt.Sprintf("%s_space", id)), "SIZE", "BYTES")
+	compactionRead.Save(config.ResultFile(fmt.Sprintf("%s_compaction_read", id)), "SIZE", "BYTES")
+	compactionWrite.Save(config.ResultFile(fmt.Sprintf("%s_compaction_write", id)), "SIZE", "BYTES")
+	saveLevelTableCounts(config.ResultFile(fmt.Sprintf("%s_levels", id)), levelCounts)
+}
+
+// saveLevelTableCounts は、データサイズごとの LevelDB レベル別 SSTable 数を CSV に書き出す。
+func saveLevelTableCounts(path string, levelCounts map[uint64][]int) {
+	file, err := os.Create(path)
+	if err != nil {
+		panic(fmt.Errorf("failed to save statistics: %w", err))
+	}
+	defer file.Close()
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	maxLevels := 0
+	for _, counts := range levelCounts {
+		if len(counts) > maxLevels {
+			maxLevels = len(counts)
+		}
+	}
+	header := []string{"SIZE"}
+	for l := 0; l < maxLevels; l++ {
+		header = append(header, fmt.Sprintf("LEVEL%d", l))
+	}
+	if err := writer.Write(header); err != nil {
+		panic(fmt.Errorf("failed to save header: %w", err))
+	}
+
+	keys := make([]uint64, 0, len(levelCounts))
+	for key := range levelCounts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i] < keys[j]
+	})
+	for _, key := range keys {
+		counts := levelCounts[key]
+		record := make([]string, maxLevels+1)
+		record[0] = strconv.FormatUint(key, 10)
+		for l := 0; l < maxLevels; l++ {
+			if l < len(counts) {
+				record[l+1] = strconv.Itoa(counts[l])
+			} else {
+				record[l+1] = "0"
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			panic(fmt.Errorf("failed to save data: %w", err))
+		}
+	}
 }
 
 // Query 性能のベンチマーク
@@ -134,24 +252,22 @@ func benchmarkQuery(config *Config, id string) {
 	is := logspace(1, MaxDataSize, QueryDivision)
 	rand.Seed(time.Now().UnixNano())
 	timeComplexity := NewStats()
-	start := time.Now()
-	for i := 0; i < MaxTrials; i++ {
+	runUntilConverged(func(i int) float64 {
 		rand.Shuffle(len(is), func(i, j int) {
 			is[i], is[j] = is[j], is[i]
 		})
 		measureQuery(config.DatabasePath("iavl"), is, timeComplexity)
-		if i+1 >= MinTrials {
-			if timeComplexity.MaxRelative() <= StdDevThreshold || time.Since(start) >= MaxDuration {
-				break
-			}
-		}
 		if (i+1)%100 == 0 {
 			fmt.Printf("  [%d/%d] n=%d: cv=%.3f\n", i+1, MaxTrials, MaxDataSize, timeComplexity.MaxRelative())
 		}
-	}
+		return timeComplexity.MaxP99()
+	})
 	config.RemoveDatabase("iavl")
 
-	timeComplexity.Save(config.ResultFile(id), "SIZE", "TIME")
+	timeComplexity.SaveSummary(config.ResultFile(id), "SIZE")
+	if config.HDR {
+		timeComplexity.Save(config.ResultFile(fmt.Sprintf("%s_raw", id)), "SIZE", "TIME")
+	}
 }
 
 // 統計情報
@@ -222,6 +338,102 @@ func (s *Stats) MaxRelative() float64 {
 	return relative
 }
 
+// Percentile は key の試行値のうち、p (0.0〜1.0) 分位点の値を線形補間で求める。
+// LSM 系ストレージのレイテンシはメモリテーブルヒットとコンパクションストールが混在する
+// 二峰性分布になりやすく、平均・標準偏差だけでは裾野の挙動を見誤るため用意している。
+func (s *Stats) Percentile(key uint64, p float64) float64 {
+	trials, ok := s.trials[key]
+	if !ok || len(trials) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), trials...)
+	sort.Float64s(sorted)
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+	idx := p * float64(len(sorted)-1)
+	lo := int(math.Floor(idx))
+	hi := int(math.Ceil(idx))
+	if lo == hi {
+		return sorted[lo]
+	}
+	frac := idx - float64(lo)
+	return sorted[lo]*(1-frac) + sorted[hi]*frac
+}
+
+// MaxP99 は記録されているすべてのキーのうち、p99 の最大値を返す。収束判定で
+// 「最も裾が重いキー」を追跡するために使う、MaxRelative の p99 版。
+func (s *Stats) MaxP99() float64 {
+	max := math.NaN()
+	for key := range s.trials {
+		p99 := s.Percentile(key, 0.99)
+		if math.IsNaN(max) || p99 > max {
+			max = p99
+		}
+	}
+	return max
+}
+
+// runUntilConverged は、MaxTrials を上限として trial を繰り返し呼び出す共通の収束ループ。
+// trial は i 回目の試行を実行したうえで収束判定に使う p99 を返す。MinTrials 回目以降は
+// 直前の p99 との相対差が StdDevThreshold 以下になるか MaxDuration に達した時点で打ち切る。
+// 各ベンチマーク関数が書き写していた収束判定ロジックを一本化したもの。
+func runUntilConverged(trial func(i int) float64) {
+	start := time.Now()
+	prevP99 := math.NaN()
+	for i := 0; i < MaxTrials; i++ {
+		p99 := trial(i)
+		if i+1 >= MinTrials {
+			if !math.IsNaN(prevP99) && math.Abs(p99-prevP99)/p99 <= StdDevThreshold || time.Since(start) >= MaxDuration {
+				break
+			}
+			prevP99 = p99
+		}
+	}
+}
+
+// SaveSummary は、キーごとに N・平均・標準偏差に加えて p50/p90/p99/p99.9/max を列として
+// 書き出す。生のトライアル値を列挙する Save と異なり、1キー1行の要約統計になる。
+func (s *Stats) SaveSummary(path, column1 string) {
+	file, err := os.Create(path)
+	if err != nil {
+		panic(fmt.Errorf("failed to save statistics: %w", err))
+	}
+	defer file.Close()
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	header := []string{column1, "N", "MEAN", "STDDEV", "P50", "P90", "P99", "P99.9", "MAX"}
+	if err := writer.Write(header); err != nil {
+		panic(fmt.Errorf("failed to save header: %w", err))
+	}
+
+	keys := make([]uint64, 0, len(s.trials))
+	for key := range s.trials {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i] < keys[j]
+	})
+	for _, key := range keys {
+		mean, stddev, n := s.Calculate(key)
+		record := []string{
+			strconv.FormatUint(key, 10),
+			strconv.Itoa(n),
+			strconv.FormatFloat(mean, 'f', -1, 64),
+			strconv.FormatFloat(stddev, 'f', -1, 64),
+			strconv.FormatFloat(s.Percentile(key, 0.50), 'f', -1, 64),
+			strconv.FormatFloat(s.Percentile(key, 0.90), 'f', -1, 64),
+			strconv.FormatFloat(s.Percentile(key, 0.99), 'f', -1, 64),
+			strconv.FormatFloat(s.Percentile(key, 0.999), 'f', -1, 64),
+			strconv.FormatFloat(s.Percentile(key, 1.0), 'f', -1, 64),
+		}
+		if err := writer.Write(record); err != nil {
+			panic(fmt.Errorf("failed to save data: %w", err))
+		}
+	}
+}
+
 func (s *Stats) Save(path, column1, column2 string) {
 	file, err := os.Create(path)
 	if err != nil {
@@ -257,9 +469,12 @@ func (s *Stats) Save(path, column1, column2 string) {
 
 // コマンドライン引数
 type Config struct {
-	WorkDir   string
-	ResultDir string
-	SessionID string
+	WorkDir      string
+	ResultDir    string
+	SessionID    string
+	BatchSizes   []uint64
+	ReaderCounts []uint64
+	HDR          bool
 }
 
 func (c *Config) DatabasePath(name string) string {
@@ -290,12 +505,18 @@ func parseCommandLine() *Config {
 		fmt.Fprintf(os.Stderr, "\nOptions:\n")
 		fmt.Fprintf(os.Stderr, "  --output DIR    Directory to save result CSV files (default: %s)\n", config.ResultDir)
 		fmt.Fprintf(os.Stderr, "  --session ID    Session name for result file naming (default: %s)\n", config.SessionID)
+		fmt.Fprintf(os.Stderr, "  --batch-size N1,N2,...  Set-per-SaveVersion batch sizes to sweep (default: %v)\n", DefaultBatchSizes)
+		fmt.Fprintf(os.Stderr, "  --readers N1,N2,...     Concurrent reader-goroutine counts to sweep (default: %v)\n", DefaultReaderCounts)
+		fmt.Fprintf(os.Stderr, "  --hdr           Also save raw per-trial latencies alongside the percentile summary\n")
 		fmt.Fprintf(os.Stderr, "  --clean         Remove all cached files and exit\n")
 		fmt.Fprintf(os.Stderr, "  --help          Show this help message\n")
 	}
 
 	flag.StringVar(&config.ResultDir, "output", DefaultResultDir, "Directory to save result CSV files")
 	flag.StringVar(&config.SessionID, "session", time.Now().Format("20060102150405"), "Session name for result file naming")
+	batchSizeFlag := flag.String("batch-size", "1,10,100,1000", "Comma-separated Set-per-SaveVersion batch sizes to sweep")
+	readersFlag := flag.String("readers", "1,2,4,8,16", "Comma-separated concurrent reader-goroutine counts to sweep")
+	flag.BoolVar(&config.HDR, "hdr", false, "Also save raw per-trial latencies alongside the percentile summary")
 	cleanFlag := flag.Bool("clean", false, "Remove all cached files and exit")
 	helpFlag := flag.Bool("help", false, "Show help message")
 
@@ -305,12 +526,14 @@ func parseCommandLine() *Config {
 		flag.Usage()
 		os.Exit(0)
 	}
+	config.BatchSizes = parseBatchSizes(*batchSizeFlag)
+	config.ReaderCounts = parseBatchSizes(*readersFlag)
 
 	if flag.NArg() > 1 {
 		panic(fmt.Errorf("too many %d arguments, expected at most one directory path", flag.NArg()))
 	}
 	dir := os.TempDir()
-	if flag.NArg() = 1 {
+	if flag.NArg() == 1 {
 		dir = flag.Arg(0)
 	}
 	config.WorkDir = createDirectory(dir)
@@ -442,4 +665,9 @@ func main() {
 	printSystemInfo(config)
 	benchmarkQuery(config, "query-iavl-leveldb")
 	benchmarkAppend(config, "append-iavl-leveldb")
+	benchmarkQueryPebble(config, "query-iavl-pebble")
+	benchmarkAppendPebble(config, "append-iavl-pebble")
+	benchmarkProof(config, "proof-iavl-leveldb")
+	benchmarkAppendBatch(config, "append-iavl-leveldb")
+	benchmarkConcurrentQuery(config, "query-iavl-leveldb-concurrent")
 }
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	corestore "cosmossdk.io/core/store"
+	"github.com/cockroachdb/pebble"
+	"github.com/cosmos/iavl"
+)
+
+// PebbleDB は github.com/cockroachdb/pebble を cosmos/iavl の dbm.DB インタフェースに
+// 適合させるアダプタです。goleveldb とは異なるコンパクション方式やブロックキャッシュの
+// 挙動を持つ LSM 実装で IAVL ツリーを比較するために使用します。
+type PebbleDB struct {
+	db *pebble.DB
+}
+
+func newPebbleDB(dir string) (*PebbleDB, error) {
+	db, err := pebble.Open(dir, &pebble.Options{})
+	if err != nil {
+		return nil, err
+	}
+	return &PebbleDB{db: db}, nil
+}
+
+// Get implements dbm.DB.
+func (d *PebbleDB) Get(key []byte) ([]byte, error) {
+	value, closer, err := d.db.Get(key)
+	if errors.Is(err, pebble.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(value))
+	copy(out, value)
+	return out, closer.Close()
+}
+
+// Has implements dbm.DB.
+func (d *PebbleDB) Has(key []byte) (bool, error) {
+	value, err := d.Get(key)
+	if err != nil {
+		return false, err
+	}
+	return value != nil, nil
+}
+
+// Set writes a key/value pair directly (not part of dbm.DB, used by the benchmark harness).
+func (d *PebbleDB) Set(key, value []byte) error {
+	return d.db.Set(key, value, pebble.NoSync)
+}
+
+// Iterator implements dbm.DB.
+func (d *PebbleDB) Iterator(start, end []byte) (corestore.Iterator, error) {
+	it, err := d.db.NewIter(&pebble.IterOptions{LowerBound: start, UpperBound: end})
+	if err != nil {
+		return nil, err
+	}
+	return newPebbleIterator(it, start, end, false), nil
+}
+
+// ReverseIterator implements dbm.DB.
+func (d *PebbleDB) ReverseIterator(start, end []byte) (corestore.Iterator, error) {
+	it, err := d.db.NewIter(&pebble.IterOptions{LowerBound: start, UpperBound: end})
+	if err != nil {
+		return nil, err
+	}
+	return newPebbleIterator(it, start, end, true), nil
+}
+
+// Close implements dbm.DB.
+func (d *PebbleDB) Close() error {
+	return d.db.Close()
+}
+
+// NewBatch implements dbm.DB.
+func (d *PebbleDB) NewBatch() corestore.Batch {
+	return &pebbleBatch{db: d.db, batch: d.db.NewBatch()}
+}
+
+// NewBatchWithSize implements dbm.DB.
+func (d *PebbleDB) NewBatchWithSize(size int) corestore.Batch {
+	return &pebbleBatch{db: d.db, batch: d.db.NewBatchWithSize(size)}
+}
+
+// Stats returns pebble's internal metrics, analogous to GoLevelDB.Stats().
+func (d *PebbleDB) Stats() *pebble.Metrics {
+	return d.db.Metrics()
+}
+
+type pebbleIterator struct {
+	source    *pebble.Iterator
+	start     []byte
+	end       []byte
+	isReverse bool
+	isInvalid bool
+}
+
+func newPebbleIterator(source *pebble.Iterator, start, end []byte, isReverse bool) *pebbleIterator {
+	if isReverse {
+		source.Last()
+	} else {
+		source.First()
+	}
+	return &pebbleIterator{source: source, start: start, end: end, isReverse: isReverse}
+}
+
+func (it *pebbleIterator) Domain() ([]byte, []byte) {
+	return it.start, it.end
+}
+
+func (it *pebbleIterator) Valid() bool {
+	if it.isInvalid {
+		return false
+	}
+	if err := it.source.Error(); err != nil {
+		it.isInvalid = true
+		return false
+	}
+	if !it.source.Valid() {
+		it.isInvalid = true
+		return false
+	}
+	key := it.source.Key()
+	if it.isReverse {
+		if it.start != nil && bytes.Compare(key, it.start) < 0 {
+			it.isInvalid = true
+			return false
+		}
+	} else if it.end != nil && bytes.Compare(key, it.end) >= 0 {
+		it.isInvalid = true
+		return false
+	}
+	return true
+}
+
+func (it *pebbleIterator) Key() []byte {
+	out := make([]byte, len(it.source.Key()))
+	copy(out, it.source.Key())
+	return out
+}
+
+func (it *pebbleIterator) Value() []byte {
+	out := make([]byte, len(it.source.Value()))
+	copy(out, it.source.Value())
+	return out
+}
+
+func (it *pebbleIterator) Next() {
+	if it.isReverse {
+		it.source.Prev()
+	} else {
+		it.source.Next()
+	}
+}
+
+func (it *pebbleIterator) Error() error {
+	return it.source.Error()
+}
+
+func (it *pebbleIterator) Close() error {
+	return it.source.Close()
+}
+
+type pebbleBatch struct {
+	db    *pebble.DB
+	batch *pebble.Batch
+}
+
+func (b *pebbleBatch) Set(key, value []byte) error {
+	return b.batch.Set(key, value, nil)
+}
+
+func (b *pebbleBatch) Delete(key []byte) error {
+	return b.batch.Delete(key, nil)
+}
+
+func (b *pebbleBatch) Write() error {
+	return b.db.Apply(b.batch, pebble.NoSync)
+}
+
+func (b *pebbleBatch) WriteSync() error {
+	return b.db.Apply(b.batch, pebble.Sync)
+}
+
+func (b *pebbleBatch) Close() error {
+	return b.batch.Close()
+}
+
+func (b *pebbleBatch) GetByteSize() (int, error) {
+	return int(b.batch.Len()), nil
+}
+
+// 指定されたディレクトリに Pebble ベースの IAVL を作成します。
+func measureAppendPebble(path string, n uint64, stats *Stats) int64 {
+	pdb, err := newPebbleDB(path)
+	if err != nil {
+		panic(fmt.Errorf("failed to create pebble db: %v", err))
+	}
+	defer pdb.Close()
+	tree := iavl.NewMutableTree(pdb, 0, false, iavl.NewNopLogger())
+	defer tree.Close()
+
+	start := time.Now()
+	for i := uint64(1); i <= n; i++ {
+		_, err := tree.Set(intToKey(i), intToValue(i))
+		if err != nil {
+			panic(fmt.Errorf("failed to update iavl database: %v", err))
+		}
+		_, _, err = tree.SaveVersion()
+		if err != nil {
+			panic(fmt.Errorf("failed to version iavl database: %v", err))
+		}
+	}
+	duration := time.Since(start)
+	stats.Add(n, float64(duration.Nanoseconds())/1000.0/1000.0)
+
+	return fileOrDirectorySize(path)
+}
+
+// 既存のルートハッシュから Pebble ベースの IAVL をロードし、値を取得
+func measureQueryPebble(path string, is []uint64, stats *Stats) {
+	pdb, err := newPebbleDB(path)
+	if err != nil {
+		panic(fmt.Errorf("failed to create pebble db: %v", err))
+	}
+	defer pdb.Close()
+	tree := iavl.NewMutableTree(pdb, 0, false, iavl.NewNopLogger())
+	defer tree.Close()
+
+	for _, i := range is {
+		start := time.Now()
+		bytes, err := tree.GetVersioned(intToKey(i), int64(i))
+		if err != nil {
+			panic(err)
+		}
+		duration := time.Since(start)
+		stats.Add(i, float64(duration.Nanoseconds())/1000.0/1000.0)
+		value := valueToInt(bytes)
+		if value != splitmix64(i) {
+			panic(fmt.Errorf("The value read for i=%d is incorrect: %d != %d", i, splitmix64(i), value))
+		}
+	}
+}
+
+// Append 性能のベンチマーク (Pebble バックエンド)
+func benchmarkAppendPebble(config *Config, id string) {
+	fmt.Println("=== Append Benchmark (Pebble) ===")
+	fmt.Println("DataSize\tMean(ms)\tStdDev(ms)\tCV(%)\t\tTrials")
+	fmt.Println("--------\t--------\t----------\t-----\t\t------")
+
+	ns := linspace(1, MaxDataSize, AppendDivision)
+	timeComplexity := NewStats()
+	spaceComplexity := NewStats()
+	for _, n := range ns {
+		runUntilConverged(func(i int) float64 {
+			config.RemoveDatabase("iavl-pebble")
+			space := measureAppendPebble(config.DatabasePath("iavl-pebble"), n, timeComplexity)
+			spaceComplexity.AddLarger(n, float64(space))
+			return timeComplexity.Percentile(n, 0.99)
+		})
+		mean, stddev, size := timeComplexity.Calculate(n)
+		fmt.Printf("%d\t\t%.2fms\t\t%.2fms\t\t%.2f\t\t%d\n",
+			n, mean, stddev, 2*stddev/mean, size)
+	}
+	config.RemoveDatabase("iavl-pebble")
+
+	timeComplexity.SaveSummary(config.ResultFile(fmt.Sprintf("%s_time", id)), "SIZE")
+	if config.HDR {
+		timeComplexity.Save(config.ResultFile(fmt.Sprintf("%s_time_raw", id)), "SIZE", "TIME")
+	}
+	spaceComplexity.Save(config.ResultFile(fmt.Sprintf("%s_space", id)), "SIZE", "BYTES")
+}
+
+// Query 性能のベンチマーク (Pebble バックエンド)
+func benchmarkQueryPebble(config *Config, id string) {
+	fmt.Println("\n=== Query Benchmark (Pebble) ===")
+
+	fmt.Printf("Creating iavl-pebble with %d entries...\n", MaxDataSize)
+	config.RemoveDatabase("iavl-pebble")
+	measureAppendPebble(config.DatabasePath("iavl-pebble"), MaxDataSize, NewStats())
+
+	fmt.Println("Position\tMean(μs)\tStdDev(μs)\tCV(%)\t\tTrials")
+	fmt.Println("--------\t--------\t----------\t-----\t\t------")
+
+	is := logspace(1, MaxDataSize, QueryDivision)
+	timeComplexity := NewStats()
+	runUntilConverged(func(i int) float64 {
+		rand.Shuffle(len(is), func(i, j int) {
+			is[i], is[j] = is[j], is[i]
+		})
+		measureQueryPebble(config.DatabasePath("iavl-pebble"), is, timeComplexity)
+		if (i+1)%100 == 0 {
+			fmt.Printf("  [%d/%d] n=%d: cv=%.3f\n", i+1, MaxTrials, MaxDataSize, timeComplexity.MaxRelative())
+		}
+		return timeComplexity.MaxP99()
+	})
+	config.RemoveDatabase("iavl-pebble")
+
+	timeComplexity.SaveSummary(config.ResultFile(id), "SIZE")
+	if config.HDR {
+		timeComplexity.Save(config.ResultFile(fmt.Sprintf("%s_raw", id)), "SIZE", "TIME")
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cosmos/ics23/go"
+
+	"github.com/cosmos/iavl"
+	"github.com/cosmos/iavl/db"
+)
+
+// measureProof は、満杯のツリーから対数的に選ばれたキーについて ICS-23 証明の生成時間、
+// シリアライズされた証明サイズ、および検証時間を計測する。IAVL は本来このような
+// Merkle 証明の提供のために存在しており、これはその実際のワークロードを表す。
+func measureProof(path string, is []uint64, genTime, proofSize, verifyTime *Stats) {
+
+	// 保存された場所から IAVL をロード
+	leveldb, err := db.NewGoLevelDB("slate", path)
+	if err != nil {
+		panic(fmt.Errorf("failed to create leveldb: %v", err))
+	}
+	defer leveldb.Close()
+	tree := iavl.NewMutableTree(leveldb, 0, false, iavl.NewNopLogger())
+	defer tree.Close()
+	_, err = tree.Load()
+	if err != nil {
+		panic(fmt.Errorf("failed to load tree: %v", err))
+	}
+	root := tree.Hash()
+
+	for _, i := range is {
+		key := intToKey(i)
+
+		start := time.Now()
+		proof, err := tree.GetMembershipProof(key)
+		if err != nil {
+			panic(fmt.Errorf("failed to generate membership proof for i=%d: %v", i, err))
+		}
+		genTime.Add(i, float64(time.Since(start).Nanoseconds())/1000.0/1000.0)
+
+		bz, err := proof.Marshal()
+		if err != nil {
+			panic(fmt.Errorf("failed to serialize proof for i=%d: %v", i, err))
+		}
+		proofSize.Add(i, float64(len(bz)))
+
+		start = time.Now()
+		if !ics23.VerifyMembership(ics23.IavlSpec, root, proof, key, intToValue(i)) {
+			panic(fmt.Errorf("membership proof for i=%d did not verify", i))
+		}
+		verifyTime.Add(i, float64(time.Since(start).Nanoseconds())/1000.0/1000.0)
+	}
+}
+
+// measureNonMembershipProof は、ツリーに存在しないキーについて ICS-23 非包含証明の生成時間、
+// シリアライズされた証明サイズ、および検証時間を計測する。is の各値に MaxDataSize を
+// 足したキーを使うことで、挿入済みの範囲 1..MaxDataSize と重ならないことを保証する。
+func measureNonMembershipProof(path string, is []uint64, genTime, proofSize, verifyTime *Stats) {
+	leveldb, err := db.NewGoLevelDB("slate", path)
+	if err != nil {
+		panic(fmt.Errorf("failed to create leveldb: %v", err))
+	}
+	defer leveldb.Close()
+	tree := iavl.NewMutableTree(leveldb, 0, false, iavl.NewNopLogger())
+	defer tree.Close()
+	_, err = tree.Load()
+	if err != nil {
+		panic(fmt.Errorf("failed to load tree: %v", err))
+	}
+	root := tree.Hash()
+
+	for _, i := range is {
+		key := intToKey(i + MaxDataSize)
+
+		start := time.Now()
+		proof, err := tree.GetNonMembershipProof(key)
+		if err != nil {
+			panic(fmt.Errorf("failed to generate non-membership proof for i=%d: %v", i, err))
+		}
+		genTime.Add(i, float64(time.Since(start).Nanoseconds())/1000.0/1000.0)
+
+		bz, err := proof.Marshal()
+		if err != nil {
+			panic(fmt.Errorf("failed to serialize proof for i=%d: %v", i, err))
+		}
+		proofSize.Add(i, float64(len(bz)))
+
+		start = time.Now()
+		if !ics23.VerifyNonMembership(ics23.IavlSpec, root, proof, key) {
+			panic(fmt.Errorf("non-membership proof for i=%d did not verify", i))
+		}
+		verifyTime.Add(i, float64(time.Since(start).Nanoseconds())/1000.0/1000.0)
+	}
+}
+
+// Merkle 証明性能のベンチマーク。GetMembershipProof/GetNonMembershipProof の両方を計測する。
+func benchmarkProof(config *Config, id string) {
+	fmt.Println("\n=== Proof Benchmark ===")
+
+	fmt.Printf("Creating iavl with %d entries...\n", MaxDataSize)
+	config.RemoveDatabase("iavl")
+	measureAppend(config.DatabasePath("iavl"), MaxDataSize, NewStats())
+
+	fmt.Println("Position\tGen(μs)\t\tSize(bytes)\tVerify(μs)\tTrials")
+	fmt.Println("--------\t--------\t-----------\t----------\t------")
+
+	is := logspace(1, MaxDataSize, QueryDivision)
+	genTime := NewStats()
+	proofSize := NewStats()
+	verifyTime := NewStats()
+	nonMemGenTime := NewStats()
+	nonMemProofSize := NewStats()
+	nonMemVerifyTime := NewStats()
+	runUntilConverged(func(i int) float64 {
+		measureProof(config.DatabasePath("iavl"), is, genTime, proofSize, verifyTime)
+		measureNonMembershipProof(config.DatabasePath("iavl"), is, nonMemGenTime, nonMemProofSize, nonMemVerifyTime)
+		if (i+1)%100 == 0 {
+			fmt.Printf("  [%d/%d] n=%d: cv=%.3f\n", i+1, MaxTrials, MaxDataSize, genTime.MaxRelative())
+		}
+		return genTime.MaxP99()
+	})
+	config.RemoveDatabase("iavl")
+
+	genTime.SaveSummary(config.ResultFile(fmt.Sprintf("%s_gen_time", id)), "SIZE")
+	if config.HDR {
+		genTime.Save(config.ResultFile(fmt.Sprintf("%s_gen_time_raw", id)), "SIZE", "TIME")
+	}
+	proofSize.Save(config.ResultFile(fmt.Sprintf("%s_size", id)), "SIZE", "BYTES")
+	verifyTime.SaveSummary(config.ResultFile(fmt.Sprintf("%s_verify_time", id)), "SIZE")
+	if config.HDR {
+		verifyTime.Save(config.ResultFile(fmt.Sprintf("%s_verify_time_raw", id)), "SIZE", "TIME")
+	}
+
+	nonMemGenTime.SaveSummary(config.ResultFile(fmt.Sprintf("%s_nonmem_gen_time", id)), "SIZE")
+	if config.HDR {
+		nonMemGenTime.Save(config.ResultFile(fmt.Sprintf("%s_nonmem_gen_time_raw", id)), "SIZE", "TIME")
+	}
+	nonMemProofSize.Save(config.ResultFile(fmt.Sprintf("%s_nonmem_size", id)), "SIZE", "BYTES")
+	nonMemVerifyTime.SaveSummary(config.ResultFile(fmt.Sprintf("%s_nonmem_verify_time", id)), "SIZE")
+	if config.HDR {
+		nonMemVerifyTime.Save(config.ResultFile(fmt.Sprintf("%s_nonmem_verify_time_raw", id)), "SIZE", "TIME")
+	}
+}